@@ -0,0 +1,147 @@
+package client
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// defaultRegion is the region that buckets are created in when no location constraint is provided.
+	defaultRegion = "us-east-1"
+
+	// legacyEURegion is the location constraint returned for buckets created in eu-west-1 with the legacy EU
+	// constraint.
+	legacyEURegion = "EU"
+)
+
+// BucketInfo represents the configuration of an Amazon S3 bucket.
+type BucketInfo struct {
+	Name   string
+	Region string
+
+	// Versioning is the versioning status of the bucket, and is empty if versioning has never been enabled.
+	Versioning string
+
+	// Encryption is the default server-side encryption algorithm of the bucket, and is empty if the bucket has
+	// no default encryption.
+	Encryption string
+	KMSKeyID   string
+}
+
+// CreateBucket creates a new bucket in the specified region, or the default region if none is provided.
+func (c Client) CreateBucket(bucket, region string) error {
+	input := s3.CreateBucketInput{
+		Bucket: &bucket,
+	}
+
+	// Amazon S3 rejects a location constraint for the default region, which is used when none is provided.
+	if len(region) > 0 && region != defaultRegion {
+		input.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+			LocationConstraint: &region,
+		}
+	}
+
+	_, err := c.s3.CreateBucket(&input)
+	return err
+}
+
+// GetBucketInfo returns the region, versioning and default encryption configuration of the specified bucket.
+func (c Client) GetBucketInfo(bucket string) (BucketInfo, error) {
+	info := BucketInfo{Name: bucket}
+
+	location, err := c.s3.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: &bucket})
+	if err != nil {
+		return info, err
+	}
+
+	// Buckets in the default region have no location constraint.
+	switch info.Region = aws.StringValue(location.LocationConstraint); info.Region {
+	case "":
+		info.Region = defaultRegion
+	case legacyEURegion:
+		info.Region = "eu-west-1"
+	}
+
+	versioning, err := c.s3.GetBucketVersioning(&s3.GetBucketVersioningInput{Bucket: &bucket})
+	if err != nil {
+		return info, err
+	}
+	info.Versioning = aws.StringValue(versioning.Status)
+
+	// A bucket without default encryption returns an error, rather than an empty configuration.
+	encryption, err := c.s3.GetBucketEncryption(&getBucketEncryptionInput{Bucket: &bucket})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == errCodeNoBucketEncryption {
+		return info, nil
+	} else if err != nil {
+		return info, err
+	}
+
+	if config := encryption.ServerSideEncryptionConfiguration; config != nil {
+		for _, r := range config.Rules {
+			if d := r.ApplyServerSideEncryptionByDefault; d != nil {
+				info.Encryption = aws.StringValue(d.SSEAlgorithm)
+				info.KMSKeyID = aws.StringValue(d.KMSMasterKeyID)
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// DeleteBucket deletes the specified bucket, which must already be empty.
+func (c Client) DeleteBucket(bucket string) error {
+	input := s3.DeleteBucketInput{
+		Bucket: &bucket,
+	}
+
+	_, err := c.s3.DeleteBucket(&input)
+	return err
+}
+
+// LsObjectVersions performs paginated requests to retrieve every version and delete marker of the objects
+// beginning with the prefix, and provides each page to fn as it is received.
+//
+// Buckets that have never been versioned return a single version of each object. Listing stops early, without
+// error, if fn returns false.
+func (c Client) LsObjectVersions(bucket, prefix string, fn func([]ObjectVersion) bool) error {
+	input := s3.ListObjectVersionsInput{
+		Bucket: &bucket,
+		Prefix: &prefix,
+	}
+
+	return c.s3.ListObjectVersionsPages(&input, func(page *s3.ListObjectVersionsOutput, lastPage bool) bool {
+		versions := make([]ObjectVersion, 0, len(page.Versions)+len(page.DeleteMarkers))
+		for _, v := range page.Versions {
+			versions = append(versions, ObjectVersion{
+				Key:       aws.StringValue(v.Key),
+				VersionID: aws.StringValue(v.VersionId),
+			})
+		}
+		for _, m := range page.DeleteMarkers {
+			versions = append(versions, ObjectVersion{
+				Key:            aws.StringValue(m.Key),
+				VersionID:      aws.StringValue(m.VersionId),
+				IsDeleteMarker: true,
+			})
+		}
+
+		return fn(versions)
+	})
+}
+
+// DeleteObjectVersions permanently deletes the specified object versions and delete markers from Amazon S3, in
+// batches of up to maxDeleteKeys per request, and returns the versions that could not be deleted.
+//
+// An error is only returned if a request fails entirely, in which case any remaining batches are not attempted.
+func (c Client) DeleteObjectVersions(bucket string, versions []ObjectVersion) ([]DeleteError, error) {
+	objects := make([]*s3.ObjectIdentifier, len(versions))
+	for i, v := range versions {
+		objects[i] = &s3.ObjectIdentifier{
+			Key:       aws.String(v.Key),
+			VersionId: aws.String(v.VersionID),
+		}
+	}
+
+	return c.deleteObjects(bucket, objects)
+}
@@ -0,0 +1,285 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestClient_CreateBucket(t *testing.T) {
+	tests := []struct {
+		region     string
+		constraint string
+	}{
+		{"", ""},
+		{defaultRegion, ""},
+		{"eu-west-1", "eu-west-1"},
+	}
+
+	for _, test := range tests {
+		var mockS3 mockS3Communicator
+		mockS3.createBucketCallback = func(i *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+			if *i.Bucket != "bucket" {
+				t.Fatalf("Unexpected CreateBucketInput: %v", i)
+			}
+
+			var constraint string
+			if i.CreateBucketConfiguration != nil {
+				constraint = aws.StringValue(i.CreateBucketConfiguration.LocationConstraint)
+			}
+			if constraint != test.constraint {
+				t.Fatalf("Unexpected location constraint for region %q: {Expected: %v, Actual: %v}", test.region, test.constraint, constraint)
+			}
+
+			return nil, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.CreateBucket("bucket", test.region); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Negative case
+	{
+		mockErr := errors.New("Mock Error")
+
+		var mockS3 mockS3Communicator
+		mockS3.createBucketCallback = func(i *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+			return nil, mockErr
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.CreateBucket("bucket", ""); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
+
+func TestClient_DeleteBucket(t *testing.T) {
+	// Positive case
+	{
+		var mockS3 mockS3Communicator
+		mockS3.deleteBucketCallback = func(i *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error) {
+			if *i.Bucket != "bucket" {
+				t.Fatalf("Unexpected DeleteBucketInput: %v", i)
+			}
+
+			return nil, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.DeleteBucket("bucket"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Negative case
+	{
+		mockErr := errors.New("Mock Error")
+
+		var mockS3 mockS3Communicator
+		mockS3.deleteBucketCallback = func(i *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error) {
+			return nil, mockErr
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.DeleteBucket("bucket"); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
+
+func TestClient_GetBucketInfo(t *testing.T) {
+	noEncryption := awserr.New(errCodeNoBucketEncryption, "Not found", nil)
+	kmsEncryption := &getBucketEncryptionOutput{
+		ServerSideEncryptionConfiguration: &serverSideEncryptionConfiguration{
+			Rules: []*serverSideEncryptionRule{{
+				ApplyServerSideEncryptionByDefault: &serverSideEncryptionByDefault{
+					SSEAlgorithm:   aws.String("aws:kms"),
+					KMSMasterKeyID: aws.String("key-id"),
+				},
+			}},
+		},
+	}
+
+	tests := []struct {
+		location      *string
+		versioning    *string
+		encryption    *getBucketEncryptionOutput
+		encryptionErr error
+		expected      BucketInfo
+	}{
+		{nil, nil, nil, noEncryption, BucketInfo{Name: "bucket", Region: defaultRegion}},
+		{aws.String(legacyEURegion), aws.String("Suspended"), nil, noEncryption, BucketInfo{Name: "bucket", Region: "eu-west-1", Versioning: "Suspended"}},
+		{aws.String("ap-south-1"), aws.String("Enabled"), kmsEncryption, nil, BucketInfo{Name: "bucket", Region: "ap-south-1", Versioning: "Enabled", Encryption: "aws:kms", KMSKeyID: "key-id"}},
+	}
+
+	for _, test := range tests {
+		var mockS3 mockS3Communicator
+		mockS3.getBucketLocationCallback = func(i *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error) {
+			if *i.Bucket != "bucket" {
+				t.Fatalf("Unexpected GetBucketLocationInput: %v", i)
+			}
+			return &s3.GetBucketLocationOutput{LocationConstraint: test.location}, nil
+		}
+		mockS3.getBucketVersioningCallback = func(i *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error) {
+			if *i.Bucket != "bucket" {
+				t.Fatalf("Unexpected GetBucketVersioningInput: %v", i)
+			}
+			return &s3.GetBucketVersioningOutput{Status: test.versioning}, nil
+		}
+		mockS3.getBucketEncryptionCallback = func(i *getBucketEncryptionInput) (*getBucketEncryptionOutput, error) {
+			if *i.Bucket != "bucket" {
+				t.Fatalf("Unexpected getBucketEncryptionInput: %v", i)
+			}
+			return test.encryption, test.encryptionErr
+		}
+
+		c := Client{s3: &mockS3}
+		if info, err := c.GetBucketInfo("bucket"); err != nil {
+			t.Fatal(err)
+		} else if info != test.expected {
+			t.Fatalf("Unexpected bucket info: {Expected: %v, Actual: %v}", test.expected, info)
+		}
+	}
+
+	// Negative cases
+	mockErr := errors.New("Mock Error")
+	for failing := 0; failing < 3; failing++ {
+		var mockS3 mockS3Communicator
+		mockS3.getBucketLocationCallback = func(i *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error) {
+			if failing == 0 {
+				return nil, mockErr
+			}
+			return &s3.GetBucketLocationOutput{}, nil
+		}
+		mockS3.getBucketVersioningCallback = func(i *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error) {
+			if failing == 1 {
+				return nil, mockErr
+			}
+			return &s3.GetBucketVersioningOutput{}, nil
+		}
+		mockS3.getBucketEncryptionCallback = func(i *getBucketEncryptionInput) (*getBucketEncryptionOutput, error) {
+			return nil, mockErr
+		}
+
+		c := Client{s3: &mockS3}
+		if _, err := c.GetBucketInfo("bucket"); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
+
+func TestClient_LsObjectVersions(t *testing.T) {
+	// Positive case
+	{
+		bucket := "bucket"
+		prefix := "folder/"
+
+		var mockS3 mockS3Communicator
+		mockS3.listObjectVersionsPagesCallback = func(i *s3.ListObjectVersionsInput, fn func(*s3.ListObjectVersionsOutput, bool) bool) error {
+			if *i.Bucket != bucket || *i.Prefix != prefix {
+				t.Fatalf("Unexpected ListObjectVersionsInput: %v", i)
+			}
+
+			fn(&s3.ListObjectVersionsOutput{
+				Versions: []*s3.ObjectVersion{
+					{Key: aws.String("folder/a.txt"), VersionId: aws.String("v2")},
+					{Key: aws.String("folder/a.txt"), VersionId: aws.String("v1")},
+				},
+				DeleteMarkers: []*s3.DeleteMarkerEntry{
+					{Key: aws.String("folder/b.txt"), VersionId: aws.String("v3")},
+				},
+			}, true)
+			return nil
+		}
+
+		var versions []ObjectVersion
+		c := Client{s3: &mockS3}
+		err := c.LsObjectVersions(bucket, prefix, func(page []ObjectVersion) bool {
+			versions = append(versions, page...)
+			return true
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []ObjectVersion{
+			{Key: "folder/a.txt", VersionID: "v2"},
+			{Key: "folder/a.txt", VersionID: "v1"},
+			{Key: "folder/b.txt", VersionID: "v3", IsDeleteMarker: true},
+		}
+		if len(versions) != len(expected) {
+			t.Fatalf("Unexpected versions: %v", versions)
+		}
+		for i := range expected {
+			if versions[i] != expected[i] {
+				t.Fatalf("Unexpected version: {Expected: %v, Actual: %v}", expected[i], versions[i])
+			}
+		}
+	}
+
+	// Negative case
+	{
+		mockErr := errors.New("Mock Error")
+
+		var mockS3 mockS3Communicator
+		mockS3.listObjectVersionsPagesCallback = func(i *s3.ListObjectVersionsInput, fn func(*s3.ListObjectVersionsOutput, bool) bool) error {
+			return mockErr
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.LsObjectVersions("bucket", "", func([]ObjectVersion) bool { return true }); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
+
+func TestClient_DeleteObjectVersions(t *testing.T) {
+	// Positive case
+	{
+		versions := []ObjectVersion{
+			{Key: "a.txt", VersionID: "v1"},
+			{Key: "b.txt", VersionID: "v2", IsDeleteMarker: true},
+		}
+
+		var mockS3 mockS3Communicator
+		mockS3.deleteObjectsCallback = func(i *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			if *i.Bucket != "bucket" || len(i.Delete.Objects) != len(versions) {
+				t.Fatalf("Unexpected DeleteObjectsInput: %v", i)
+			}
+
+			for j, o := range i.Delete.Objects {
+				if *o.Key != versions[j].Key || *o.VersionId != versions[j].VersionID {
+					t.Fatalf("Unexpected ObjectIdentifier: %v", o)
+				}
+			}
+
+			return &s3.DeleteObjectsOutput{}, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if failed, err := c.DeleteObjectVersions("bucket", versions); err != nil || len(failed) != 0 {
+			t.Fatalf("Unexpected response: %v, %v", failed, err)
+		}
+	}
+
+	// Negative case
+	{
+		mockErr := errors.New("Mock Error")
+
+		var mockS3 mockS3Communicator
+		mockS3.deleteObjectsCallback = func(i *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			return nil, mockErr
+		}
+
+		c := Client{s3: &mockS3}
+		if _, err := c.DeleteObjectVersions("bucket", []ObjectVersion{{Key: "a.txt"}}); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
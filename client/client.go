@@ -2,19 +2,43 @@
 package client
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
+	"os/user"
 	"path/filepath"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
+const (
+	// pathDelimiter is the delimiter used to group object keys into folders.
+	pathDelimiter = "/"
+
+	// maxDeleteKeys is the maximum number of keys that can be deleted in a single request.
+	maxDeleteKeys = 1000
+
+	// journalDir is the directory, relative to the home directory of the current user, where the progress of
+	// transfers is recorded.
+	journalDir = ".s3fs/transfers"
+
+	// errCodeNotFound is the error code returned by a HEAD request for an object that does not exist.
+	errCodeNotFound = "NotFound"
+)
+
+// ErrObjectNotFound is returned when the specified object does not exist.
+var ErrObjectNotFound = errors.New("Object not found")
+
 // Client defines a wrapper for the Amazon S3 API.
 type Client struct {
-	s3 s3Communicator
+	s3      s3Communicator
+	journal *Journal
 }
 
 // LsBuckets performs a request to retrieve all buckets, and returns their names.
@@ -34,27 +58,59 @@ func (c Client) LsBuckets() ([]string, error) {
 	return buckets, nil
 }
 
-// LsObjects performs a request to retrieve all objects, and returns their keys.
-func (c Client) LsObjects(bucket, prefix string) ([]string, error) {
+// LsObjects performs paginated requests to retrieve all objects that begin with the prefix, and provides them
+// to fn one page at a time as they are received.
+//
+// Listing stops early, without error, if fn returns false.
+func (c Client) LsObjects(bucket, prefix string, fn func([]Object) bool) error {
 	// Initialize the S3 request.
-	input := s3.ListObjectsInput{
-		Bucket: &bucket,
-		Prefix: &prefix,
+	input := s3.ListObjectsV2Input{
+		Bucket:     &bucket,
+		Prefix:     &prefix,
+		FetchOwner: aws.Bool(true),
 	}
 
-	// Get the object list from AWS.
-	resp, err := c.s3.ListObjects(&input)
-	if err != nil {
-		return nil, err
-	}
+	// Walk each page of the object list from AWS, using the continuation token of the previous page.
+	return c.s3.ListObjectsV2Pages(&input, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		// Create a slice of objects for the page.
+		objects := make([]Object, len(page.Contents))
+		for i, o := range page.Contents {
+			objects[i] = newObject(o)
+		}
+
+		return fn(objects)
+	})
+}
 
-	// Create a slice of object keys to return.
-	objects := make([]string, len(resp.Contents))
-	for i, o := range resp.Contents {
-		objects[i] = *o.Key
+// LsDir performs paginated requests to retrieve the immediate contents of the folder represented by the prefix,
+// and provides the folders and files of each page to fn as they are received.
+//
+// Folders are the common prefixes returned by S3 when grouping by the path delimiter, and include the prefix and
+// trailing delimiter. Files are the objects directly within the prefix. Listing stops early, without error,
+// if fn returns false.
+func (c Client) LsDir(bucket, prefix string, fn func(folders []string, files []Object) bool) error {
+	// Initialize the S3 request, grouping nested keys by the path delimiter.
+	input := s3.ListObjectsV2Input{
+		Bucket:     &bucket,
+		Prefix:     &prefix,
+		Delimiter:  aws.String(pathDelimiter),
+		FetchOwner: aws.Bool(true),
 	}
 
-	return objects, nil
+	// Walk each page of the folder contents from AWS.
+	return c.s3.ListObjectsV2Pages(&input, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		folders := make([]string, len(page.CommonPrefixes))
+		for i, p := range page.CommonPrefixes {
+			folders[i] = *p.Prefix
+		}
+
+		files := make([]Object, len(page.Contents))
+		for i, o := range page.Contents {
+			files[i] = newObject(o)
+		}
+
+		return fn(folders, files)
+	})
 }
 
 // BucketExists returns a bool indicating if the specified bucket exists.
@@ -90,6 +146,24 @@ func (c Client) ObjectExists(bucket, key string) (bool, error) {
 	return true, nil
 }
 
+// HeadObject returns the metadata of the specified object, without its contents, or ErrObjectNotFound if the
+// object does not exist.
+func (c Client) HeadObject(bucket, key string) (ObjectInfo, error) {
+	input := s3.HeadObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	}
+
+	output, err := c.s3.HeadObject(&input)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == errCodeNotFound {
+		return ObjectInfo{}, ErrObjectNotFound
+	} else if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	return newObjectInfo(key, output), nil
+}
+
 // PathExists returns a bool indicating if the specified path exists in a given bucket.
 func (c Client) PathExists(bucket, path string) (bool, error) {
 	// Note: Using a HEAD request (HeadObject) won't work because S3 has no real concept of folders.
@@ -112,44 +186,104 @@ func (c Client) PathExists(bucket, path string) (bool, error) {
 	return len(resp.Contents) > 0, nil
 }
 
-// DownloadObject downloads the specified object from Amazon S3 and returns the name of a local temporary file
-// containing the downloaded object.
+// DeleteObject deletes the specified object from Amazon S3.
+func (c Client) DeleteObject(bucket, key string) error {
+	input := s3.DeleteObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	}
+
+	_, err := c.s3.DeleteObject(&input)
+	return err
+}
+
+// DeleteObjects deletes the specified objects from Amazon S3, in batches of up to maxDeleteKeys per request, and
+// returns the objects that could not be deleted.
+//
+// An error is only returned if a request fails entirely, in which case any remaining batches are not attempted.
+func (c Client) DeleteObjects(bucket string, keys []string) ([]DeleteError, error) {
+	objects := make([]*s3.ObjectIdentifier, len(keys))
+	for i, key := range keys {
+		objects[i] = &s3.ObjectIdentifier{Key: aws.String(key)}
+	}
+
+	return c.deleteObjects(bucket, objects)
+}
+
+// deleteObjects deletes the identified objects in batches of up to maxDeleteKeys per request, and returns the
+// objects that could not be deleted.
+func (c Client) deleteObjects(bucket string, objects []*s3.ObjectIdentifier) ([]DeleteError, error) {
+	var failed []DeleteError
+
+	for start := 0; start < len(objects); start += maxDeleteKeys {
+		end := start + maxDeleteKeys
+		if end > len(objects) {
+			end = len(objects)
+		}
+
+		// Construct the batch, requesting that only failures are returned.
+		input := s3.DeleteObjectsInput{
+			Bucket: &bucket,
+			Delete: &s3.Delete{
+				Objects: objects[start:end],
+				Quiet:   aws.Bool(true),
+			},
+		}
+
+		resp, err := c.s3.DeleteObjects(&input)
+		if err != nil {
+			return failed, err
+		}
+
+		for _, e := range resp.Errors {
+			failed = append(failed, newDeleteError(e))
+		}
+	}
+
+	return failed, nil
+}
+
+// OpenObject opens the specified object for reading, streaming its contents from Amazon S3 as they are read.
 //
-// Note: It is the responsibility of the caller to clean up the temporary file as necessary.
-func (c Client) DownloadObject(bucket, key string) (string, error) {
-	// Construct the request.
+// Note: It is the responsibility of the caller to close the returned reader.
+func (c Client) OpenObject(bucket, key string) (io.ReadCloser, error) {
 	input := s3.GetObjectInput{
 		Bucket: &bucket,
 		Key:    &key,
 	}
 
-	// Perform the API request to get the object.
 	output, err := c.s3.GetObject(&input)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer output.Body.Close()
 
-	// Create the temporary file.
-	tmp, err := ioutil.TempFile(os.TempDir(), "")
-	if err != nil {
-		return "", err
+	return output.Body, nil
+}
+
+// OpenObjectRange opens the inclusive byte range of the specified object for reading, so that only the bytes
+// required are requested from Amazon S3.
+//
+// Note: It is the responsibility of the caller to close the returned reader.
+func (c Client) OpenObjectRange(bucket, key string, start, end int64) (io.ReadCloser, error) {
+	input := s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
 	}
-	defer tmp.Close()
 
-	// Perform the write.
-	if _, err := io.Copy(tmp, output.Body); err != nil {
-		return "", err
+	output, err := c.s3.GetObject(&input)
+	if err != nil {
+		return nil, err
 	}
 
-	return tmp.Name(), nil
+	return output.Body, nil
 }
 
 // UploadObject uploads a file to the specified key in an Amazon S3 bucket.
 //
 // Note: If the key provided is a directory, the file will be stored in the directory with the
 // same name as the original file. If the key exists, it will be overwritten.
-func (c Client) UploadObject(bucket, key string, file *os.File) (string, error) {
+func (c Client) UploadObject(bucket, key string, file *os.File, opts UploadOptions) (string, error) {
 	// Sanitize the key input if it's empty or is a directory.
 	if len(key) == 0 {
 		key = filepath.Base(file.Name())
@@ -167,24 +301,41 @@ func (c Client) UploadObject(bucket, key string, file *os.File) (string, error)
 		}
 	}
 
-	// Perform the upload.
-	input := s3.PutObjectInput{
-		Bucket: &bucket,
-		Key:    &key,
-		Body:   file,
-	}
-	if _, err := c.s3.PutObject(&input); err != nil {
+	if err := c.PutObject(bucket, key, file, opts); err != nil {
 		return "", err
 	}
 
 	return key, nil
 }
 
+// CreateFolder creates an empty folder by writing a zero-byte marker object, named for the folder and ending in
+// the path delimiter, as the Amazon S3 console does.
+func (c Client) CreateFolder(bucket, prefix string) error {
+	if !strings.HasSuffix(prefix, pathDelimiter) {
+		prefix = prefix + pathDelimiter
+	}
+
+	input := s3.PutObjectInput{
+		Bucket: &bucket,
+		Key:    &prefix,
+		Body:   bytes.NewReader(nil),
+	}
+	_, err := c.s3.PutObject(&input)
+	return err
+}
+
 // New returns an initialized Client.
 func New(region string) Client {
-	return Client{
-		s3: s3.New(session.New(), &aws.Config{
+	c := Client{
+		s3: s3Service{s3.New(session.New(), &aws.Config{
 			Region: aws.String(region),
-		}),
+		})},
 	}
+
+	// Transfers can only be resumed if there is somewhere to record them.
+	if usr, err := user.Current(); err == nil {
+		c.journal = NewJournal(filepath.Join(usr.HomeDir, filepath.FromSlash(journalDir)))
+	}
+
+	return c
 }
@@ -2,12 +2,15 @@ package client
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
@@ -25,7 +28,7 @@ func TestClient_LsBuckets(t *testing.T) {
 			return &sample, nil
 		}
 
-		c := Client{&mockS3}
+		c := Client{s3: &mockS3}
 
 		buckets, err := c.LsBuckets()
 		if err != nil {
@@ -50,7 +53,7 @@ func TestClient_LsBuckets(t *testing.T) {
 			return nil, mockErr
 		}
 
-		c := Client{&mockS3}
+		c := Client{s3: &mockS3}
 
 		if _, err := c.LsBuckets(); err != mockErr {
 			t.Fatalf("Unexpected error returned: %v", err)
@@ -59,41 +62,80 @@ func TestClient_LsBuckets(t *testing.T) {
 }
 
 func TestClient_LsObjects(t *testing.T) {
-	// Positive case
+	// Positive case, multiple pages
 	{
 		bucket := "bucket"
 		prefix := "prefix"
-		sample := s3.ListObjectsOutput{
-			Contents: []*s3.Object{
-				{Key: aws.String("test")},
-			},
+		pages := []*s3.ListObjectsV2Output{
+			{Contents: []*s3.Object{{Key: aws.String("test1")}, {Key: aws.String("test2")}}},
+			{Contents: []*s3.Object{{Key: aws.String("test3")}}},
 		}
 
 		var mockS3 mockS3Communicator
-		mockS3.listObjectsCallback = func(i *s3.ListObjectsInput) (*s3.ListObjectsOutput, error) {
-			if *i.Bucket != bucket || *i.Prefix != prefix {
-				t.Fatalf("Unexpected ListObjectsInput: %v", i)
+		mockS3.listObjectsV2PagesCallback = func(i *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+			if *i.Bucket != bucket || *i.Prefix != prefix || !*i.FetchOwner {
+				t.Fatalf("Unexpected ListObjectsV2Input: %v", i)
 			}
 
-			return &sample, nil
+			for n, page := range pages {
+				if !fn(page, n == len(pages)-1) {
+					break
+				}
+			}
+			return nil
 		}
 
-		c := Client{&mockS3}
+		c := Client{s3: &mockS3}
 
-		objects, err := c.LsObjects(bucket, prefix)
+		var objects []Object
+		err := c.LsObjects(bucket, prefix, func(page []Object) bool {
+			objects = append(objects, page...)
+			return true
+		})
 		if err != nil {
 			t.Fatal(err)
-		} else if len(objects) != len(sample.Contents) {
+		} else if len(objects) != 3 {
 			t.Fatalf("Unexpected number of objects returned: %v", objects)
 		}
 
-		for i, obj := range objects {
-			if obj != *sample.Contents[i].Key {
-				t.Fatalf("Unexpected response from LsObjects: {Expected: %v, Actual: %v}", *sample.Contents[i].Key, obj)
+		for i, obj := range []string{"test1", "test2", "test3"} {
+			if objects[i].Key != obj {
+				t.Fatalf("Unexpected response from LsObjects: {Expected: %v, Actual: %v}", obj, objects[i].Key)
 			}
 		}
 	}
 
+	// Stop early
+	{
+		pages := []*s3.ListObjectsV2Output{
+			{Contents: []*s3.Object{{Key: aws.String("test1")}}},
+			{Contents: []*s3.Object{{Key: aws.String("test2")}}},
+		}
+
+		var mockS3 mockS3Communicator
+		mockS3.listObjectsV2PagesCallback = func(i *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+			for n, page := range pages {
+				if !fn(page, n == len(pages)-1) {
+					break
+				}
+			}
+			return nil
+		}
+
+		c := Client{s3: &mockS3}
+
+		var calls int
+		err := c.LsObjects("bucket", "prefix", func([]Object) bool {
+			calls++
+			return false
+		})
+		if err != nil {
+			t.Fatal(err)
+		} else if calls != 1 {
+			t.Fatalf("Expected listing to stop after the first page: %v", calls)
+		}
+	}
+
 	// Negative case
 	{
 		bucket := "bucket"
@@ -101,13 +143,77 @@ func TestClient_LsObjects(t *testing.T) {
 		mockErr := errors.New("Mock Error")
 
 		var mockS3 mockS3Communicator
-		mockS3.listObjectsCallback = func(i *s3.ListObjectsInput) (*s3.ListObjectsOutput, error) {
-			return nil, mockErr
+		mockS3.listObjectsV2PagesCallback = func(i *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+			return mockErr
 		}
 
-		c := Client{&mockS3}
+		c := Client{s3: &mockS3}
 
-		if _, err := c.LsObjects(bucket, prefix); err != mockErr {
+		if err := c.LsObjects(bucket, prefix, func([]Object) bool { return true }); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
+
+func TestClient_LsDir(t *testing.T) {
+	// Positive case, multiple pages
+	{
+		bucket := "bucket"
+		prefix := "prefix/"
+		pages := []*s3.ListObjectsV2Output{
+			{
+				CommonPrefixes: []*s3.CommonPrefix{{Prefix: aws.String("prefix/folder1/")}},
+				Contents:       []*s3.Object{{Key: aws.String("prefix/file1.txt")}},
+			},
+			{
+				CommonPrefixes: []*s3.CommonPrefix{{Prefix: aws.String("prefix/folder2/")}},
+			},
+		}
+
+		var mockS3 mockS3Communicator
+		mockS3.listObjectsV2PagesCallback = func(i *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+			if *i.Bucket != bucket || *i.Prefix != prefix || *i.Delimiter != pathDelimiter {
+				t.Fatalf("Unexpected ListObjectsV2Input: %v", i)
+			}
+
+			for n, page := range pages {
+				if !fn(page, n == len(pages)-1) {
+					break
+				}
+			}
+			return nil
+		}
+
+		c := Client{s3: &mockS3}
+
+		var folders []string
+		var files []Object
+		err := c.LsDir(bucket, prefix, func(fo []string, fi []Object) bool {
+			folders = append(folders, fo...)
+			files = append(files, fi...)
+			return true
+		})
+		if err != nil {
+			t.Fatal(err)
+		} else if len(folders) != 2 || folders[0] != "prefix/folder1/" || folders[1] != "prefix/folder2/" {
+			t.Fatalf("Unexpected folders returned: %v", folders)
+		} else if len(files) != 1 || files[0].Key != "prefix/file1.txt" {
+			t.Fatalf("Unexpected files returned: %v", files)
+		}
+	}
+
+	// Negative case
+	{
+		mockErr := errors.New("Mock Error")
+
+		var mockS3 mockS3Communicator
+		mockS3.listObjectsV2PagesCallback = func(i *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+			return mockErr
+		}
+
+		c := Client{s3: &mockS3}
+
+		if err := c.LsDir("bucket", "", func([]string, []Object) bool { return true }); err != mockErr {
 			t.Fatalf("Unexpected error returned: %v", err)
 		}
 	}
@@ -127,7 +233,7 @@ func TestClient_BucketExists(t *testing.T) {
 			return nil, nil
 		}
 
-		c := Client{&mockS3}
+		c := Client{s3: &mockS3}
 		if exists, err := c.BucketExists(bucket); err != nil {
 			t.Fatal(err)
 		} else if !exists {
@@ -144,7 +250,7 @@ func TestClient_BucketExists(t *testing.T) {
 			return nil, errors.New("Fake error")
 		}
 
-		c := Client{&mockS3}
+		c := Client{s3: &mockS3}
 		if exists, err := c.BucketExists(bucket); err != nil {
 			t.Fatal(err)
 		} else if exists {
@@ -168,7 +274,7 @@ func TestClient_ObjectExists(t *testing.T) {
 			return nil, nil
 		}
 
-		c := Client{&mockS3}
+		c := Client{s3: &mockS3}
 		if exists, err := c.ObjectExists(bucket, key); err != nil {
 			t.Fatal(err)
 		} else if !exists {
@@ -186,7 +292,7 @@ func TestClient_ObjectExists(t *testing.T) {
 			return nil, errors.New("Fake Error")
 		}
 
-		c := Client{&mockS3}
+		c := Client{s3: &mockS3}
 		if exists, err := c.ObjectExists(bucket, key); err != nil {
 			t.Fatal(err)
 		} else if exists {
@@ -195,6 +301,58 @@ func TestClient_ObjectExists(t *testing.T) {
 	}
 }
 
+func TestClient_HeadObject(t *testing.T) {
+	// Positive case
+	{
+		var mockS3 mockS3Communicator
+		mockS3.headObjectCallback = func(i *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			if *i.Bucket != "bucket" || *i.Key != "key" {
+				t.Fatalf("Unexpected HeadObjectInput: %v", i)
+			}
+
+			return &s3.HeadObjectOutput{
+				ContentLength: aws.Int64(10),
+				ContentType:   aws.String("text/plain"),
+			}, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if info, err := c.HeadObject("bucket", "key"); err != nil {
+			t.Fatal(err)
+		} else if info.Key != "key" || info.Size != 10 || info.ContentType != "text/plain" {
+			t.Fatalf("Unexpected object info: %v", info)
+		}
+	}
+
+	// Negative case
+	{
+		mockErr := errors.New("Mock Error")
+
+		var mockS3 mockS3Communicator
+		mockS3.headObjectCallback = func(i *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return nil, mockErr
+		}
+
+		c := Client{s3: &mockS3}
+		if _, err := c.HeadObject("bucket", "key"); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+
+	// Not found
+	{
+		var mockS3 mockS3Communicator
+		mockS3.headObjectCallback = func(i *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return nil, awserr.New(errCodeNotFound, "Not Found", nil)
+		}
+
+		c := Client{s3: &mockS3}
+		if _, err := c.HeadObject("bucket", "key"); err != ErrObjectNotFound {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
+
 func TestClient_PathExists(t *testing.T) {
 	// Positive case
 	{
@@ -212,7 +370,7 @@ func TestClient_PathExists(t *testing.T) {
 			}, nil
 		}
 
-		c := Client{&mockS3}
+		c := Client{s3: &mockS3}
 		if exists, err := c.PathExists(bucket, key); err != nil {
 			t.Fatal(err)
 		} else if !exists {
@@ -231,7 +389,7 @@ func TestClient_PathExists(t *testing.T) {
 			return nil, mockErr
 		}
 
-		c := Client{&mockS3}
+		c := Client{s3: &mockS3}
 		if exists, err := c.PathExists(bucket, key); err != mockErr {
 			t.Fatalf("Unexpected error returned: %v", err)
 		} else if exists {
@@ -240,19 +398,120 @@ func TestClient_PathExists(t *testing.T) {
 	}
 }
 
-func TestClient_DownloadObject(t *testing.T) {
-	// Positive Case
+func TestClient_DeleteObject(t *testing.T) {
+	// Positive case
 	{
 		bucket := "bucket"
 		key := "key"
 
-		// Create a mock file to download.
-		data := make([]byte, 10000)
-		for i := 0; i < len(data); i++ {
-			data[i] = byte(i)
+		var mockS3 mockS3Communicator
+		mockS3.deleteObjectCallback = func(i *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+			if *i.Bucket != bucket || *i.Key != key {
+				t.Fatalf("Unexpected DeleteObjectInput: %v", i)
+			}
+
+			return nil, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.DeleteObject(bucket, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Negative case
+	{
+		mockErr := errors.New("Mock Error")
+
+		var mockS3 mockS3Communicator
+		mockS3.deleteObjectCallback = func(i *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+			return nil, mockErr
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.DeleteObject("bucket", "key"); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
+
+func TestClient_DeleteObjects(t *testing.T) {
+	// Positive case, multiple batches with failures
+	{
+		bucket := "bucket"
+		keys := make([]string, maxDeleteKeys+1)
+		for i := range keys {
+			keys[i] = fmt.Sprintf("key%v", i)
 		}
 
-		// Override the getObjectCallback to return the mock file.
+		var batches [][]*s3.ObjectIdentifier
+		var mockS3 mockS3Communicator
+		mockS3.deleteObjectsCallback = func(i *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			if *i.Bucket != bucket || !*i.Delete.Quiet {
+				t.Fatalf("Unexpected DeleteObjectsInput: %v", i)
+			}
+			batches = append(batches, i.Delete.Objects)
+
+			// Fail the first key of each batch.
+			return &s3.DeleteObjectsOutput{
+				Errors: []*s3.Error{{Key: i.Delete.Objects[0].Key, Code: aws.String("AccessDenied"), Message: aws.String("Access Denied")}},
+			}, nil
+		}
+
+		c := Client{s3: &mockS3}
+		failed, err := c.DeleteObjects(bucket, keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(batches) != 2 || len(batches[0]) != maxDeleteKeys || len(batches[1]) != 1 {
+			t.Fatalf("Unexpected batches: %v", len(batches))
+		} else if *batches[1][0].Key != keys[maxDeleteKeys] {
+			t.Fatalf("Unexpected key in final batch: %v", *batches[1][0].Key)
+		}
+
+		if len(failed) != 2 || failed[0].Key != keys[0] || failed[1].Key != keys[maxDeleteKeys] || failed[0].Code != "AccessDenied" {
+			t.Fatalf("Unexpected failures returned: %v", failed)
+		}
+	}
+
+	// No keys
+	{
+		var mockS3 mockS3Communicator
+		mockS3.deleteObjectsCallback = func(i *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			t.Fatal("DeleteObjects should not be called without keys")
+			return nil, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if failed, err := c.DeleteObjects("bucket", nil); err != nil || len(failed) != 0 {
+			t.Fatalf("Unexpected response without keys: %v, %v", failed, err)
+		}
+	}
+
+	// Negative case
+	{
+		mockErr := errors.New("Mock Error")
+
+		var mockS3 mockS3Communicator
+		mockS3.deleteObjectsCallback = func(i *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			return nil, mockErr
+		}
+
+		c := Client{s3: &mockS3}
+		if _, err := c.DeleteObjects("bucket", []string{"key"}); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
+
+func TestClient_OpenObject(t *testing.T) {
+	// Positive case
+	{
+		bucket := "bucket"
+		key := "key"
+		data := []byte("Hello Object")
+
 		var mockS3 mockS3Communicator
 		mockS3.getObjectCallback = func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
 			if *i.Bucket != bucket || *i.Key != key {
@@ -266,48 +525,82 @@ func TestClient_DownloadObject(t *testing.T) {
 			}, nil
 		}
 
-		// Download the sample object.
-		c := Client{&mockS3}
-		f, err := c.DownloadObject(bucket, key)
+		c := Client{s3: &mockS3}
+		r, err := c.OpenObject(bucket, key)
 		if err != nil {
 			t.Fatal(err)
 		}
-		defer os.Remove(f)
+		defer r.Close()
 
-		// Ensure the correct file contents were written.
-		contents, err := ioutil.ReadFile(f)
-		if err != nil {
+		if contents, err := ioutil.ReadAll(r); err != nil {
 			t.Fatal(err)
+		} else if string(contents) != string(data) {
+			t.Fatalf("Unexpected object contents: {Expected: %s, Actual: %s}", data, contents)
 		}
+	}
+
+	// S3 Error
+	{
+		mockErr := errors.New("Mock Error")
 
-		if len(contents) != len(data) {
-			t.Fatalf("Downloaded file length mismatch: {Expected: %v, Actual: %v}", len(data), len(contents))
+		var mockS3 mockS3Communicator
+		mockS3.getObjectCallback = func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			return nil, mockErr
 		}
 
-		for i := 0; i < len(contents); i++ {
-			if contents[i] != data[i] {
-				t.Fatal("Downloaded file contents incorrect!")
-			}
+		c := Client{s3: &mockS3}
+		if _, err := c.OpenObject("bucket", "key"); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
 		}
 	}
+}
 
-	// S3 Error
+func TestClient_OpenObjectRange(t *testing.T) {
+	// Positive case
 	{
 		bucket := "bucket"
 		key := "key"
+		data := []byte("Hello")
+
+		var mockS3 mockS3Communicator
+		mockS3.getObjectCallback = func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			if *i.Bucket != bucket || *i.Key != key || *i.Range != "bytes=10-14" {
+				t.Fatalf("Unexpected GetObjectInput: %v", i)
+			}
+
+			return &s3.GetObjectOutput{
+				Body: &mockReadCloser{
+					data: data,
+				},
+			}, nil
+		}
+
+		c := Client{s3: &mockS3}
+		r, err := c.OpenObjectRange(bucket, key, 10, 14)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+
+		if contents, err := ioutil.ReadAll(r); err != nil {
+			t.Fatal(err)
+		} else if string(contents) != string(data) {
+			t.Fatalf("Unexpected object contents: {Expected: %s, Actual: %s}", data, contents)
+		}
+	}
+
+	// S3 Error
+	{
 		mockErr := errors.New("Mock Error")
 
-		// Override the getObjectCallback to return the mock error.
 		var mockS3 mockS3Communicator
 		mockS3.getObjectCallback = func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
 			return nil, mockErr
 		}
 
-		c := Client{&mockS3}
-		if f, err := c.DownloadObject(bucket, key); err != mockErr {
-			t.Fatalf("Expected mock error to be returned: %v", err)
-		} else if len(f) > 0 {
-			t.Fatalf("Unexpected file returned: %v", f)
+		c := Client{s3: &mockS3}
+		if _, err := c.OpenObjectRange("bucket", "key", 0, 1); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
 		}
 	}
 }
@@ -338,8 +631,8 @@ func TestClient_UploadObject(t *testing.T) {
 			}, nil
 		}
 
-		c := Client{&mockS3}
-		if path, err := c.UploadObject(bucket, key, file); err != nil {
+		c := Client{s3: &mockS3}
+		if path, err := c.UploadObject(bucket, key, file, UploadOptions{}); err != nil {
 			t.Fatal(err)
 		} else if path != key {
 			t.Fatalf("Unexpected path returned: %v", path)
@@ -368,8 +661,8 @@ func TestClient_UploadObject(t *testing.T) {
 			}, nil
 		}
 
-		c := Client{&mockS3}
-		if path, err := c.UploadObject(bucket, key, file); err != nil {
+		c := Client{s3: &mockS3}
+		if path, err := c.UploadObject(bucket, key, file, UploadOptions{}); err != nil {
 			t.Fatal(err)
 		} else if path != expectedKey {
 			t.Fatalf("Unexpected path returned: %v", path)
@@ -394,8 +687,8 @@ func TestClient_UploadObject(t *testing.T) {
 			}, nil
 		}
 
-		c := Client{&mockS3}
-		if _, err := c.UploadObject(bucket, key, file); err != mockErr {
+		c := Client{s3: &mockS3}
+		if _, err := c.UploadObject(bucket, key, file, UploadOptions{}); err != mockErr {
 			t.Fatalf("Expected mock error to be returned: %v", err)
 		}
 	}
@@ -408,3 +701,43 @@ func TestNew(t *testing.T) {
 		t.Fatal("Expected client to be initialized with an s3communicator")
 	}
 }
+
+func TestClient_CreateFolder(t *testing.T) {
+	// Positive cases, with and without a trailing delimiter
+	for _, prefix := range []string{"folder/sub", "folder/sub/"} {
+		bucket := "bucket"
+
+		var mockS3 mockS3Communicator
+		mockS3.putObjectCallback = func(i *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			if *i.Bucket != bucket || *i.Key != "folder/sub/" {
+				t.Fatalf("Unexpected PutObjectInput: %v", i)
+			}
+
+			if n, _ := i.Body.Seek(0, io.SeekEnd); n != 0 {
+				t.Fatalf("Expected an empty body: %v", n)
+			}
+
+			return nil, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.CreateFolder(bucket, prefix); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Negative case
+	{
+		mockErr := errors.New("Mock Error")
+
+		var mockS3 mockS3Communicator
+		mockS3.putObjectCallback = func(i *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			return nil, mockErr
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.CreateFolder("bucket", "folder"); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// maxCopySize is the largest object that can be copied in a single CopyObject request.
+	maxCopySize = 5 * 1024 * 1024 * 1024
+
+	// defaultCopyPartSize is the size of each part when copying objects larger than maxCopySize, unless the
+	// object is too large to be copied within maxUploadParts.
+	defaultCopyPartSize = 512 * 1024 * 1024
+)
+
+// CopyObject copies an object server-side, within or across buckets, without the object being downloaded.
+//
+// Objects larger than maxCopySize are copied in parts using a multipart upload, as required by Amazon S3.
+func (c Client) CopyObject(srcBucket, srcKey, dstBucket, dstKey string) error {
+	// Determine the size of the source object, which also validates that it exists.
+	head, err := c.s3.HeadObject(&s3.HeadObjectInput{
+		Bucket: &srcBucket,
+		Key:    &srcKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	source := copySource(srcBucket, srcKey)
+	if aws.Int64Value(head.ContentLength) > maxCopySize {
+		return c.multipartCopy(source, dstBucket, dstKey, head)
+	}
+
+	_, err = c.s3.CopyObject(&s3.CopyObjectInput{
+		Bucket:     &dstBucket,
+		Key:        &dstKey,
+		CopySource: &source,
+	})
+	return err
+}
+
+// multipartCopy copies an object in parts, using the headers, metadata and encryption of the source object for
+// the new object as a single CopyObject request does.
+//
+// If any part fails to copy, the multipart upload is aborted so that no partial object, or orphaned parts, remain.
+func (c Client) multipartCopy(source, dstBucket, dstKey string, head *s3.HeadObjectOutput) error {
+	input := s3.CreateMultipartUploadInput{
+		Bucket:                  &dstBucket,
+		Key:                     &dstKey,
+		CacheControl:            head.CacheControl,
+		ContentDisposition:      head.ContentDisposition,
+		ContentEncoding:         head.ContentEncoding,
+		ContentLanguage:         head.ContentLanguage,
+		ContentType:             head.ContentType,
+		Metadata:                head.Metadata,
+		StorageClass:            head.StorageClass,
+		ServerSideEncryption:    head.ServerSideEncryption,
+		SSEKMSKeyId:             head.SSEKMSKeyId,
+		WebsiteRedirectLocation: head.WebsiteRedirectLocation,
+	}
+	if expires, err := http.ParseTime(aws.StringValue(head.Expires)); err == nil {
+		input.Expires = &expires
+	}
+
+	upload, err := c.s3.CreateMultipartUpload(&input)
+	if err != nil {
+		return err
+	}
+
+	size := aws.Int64Value(head.ContentLength)
+	partSize := copyPartSize(size)
+	var parts []*s3.CompletedPart
+	for start, num := int64(0), int64(1); start < size; start, num = start+partSize, num+1 {
+		end := start + partSize - 1
+		if end >= size {
+			end = size - 1
+		}
+
+		resp, err := c.s3.UploadPartCopy(&s3.UploadPartCopyInput{
+			Bucket:          &dstBucket,
+			Key:             &dstKey,
+			CopySource:      &source,
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+			PartNumber:      aws.Int64(num),
+			UploadId:        upload.UploadId,
+		})
+		if err != nil {
+			c.abortMultipartUpload(dstBucket, dstKey, upload.UploadId)
+			return err
+		}
+
+		parts = append(parts, &s3.CompletedPart{
+			ETag:       resp.CopyPartResult.ETag,
+			PartNumber: aws.Int64(num),
+		})
+	}
+
+	_, err = c.s3.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          &dstBucket,
+		Key:             &dstKey,
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		c.abortMultipartUpload(dstBucket, dstKey, upload.UploadId)
+		return err
+	}
+
+	return nil
+}
+
+// abortMultipartUpload aborts a multipart upload, discarding any uploaded parts.
+//
+// Errors are ignored, as aborting is always performed in response to an earlier error which is more relevant to
+// the caller.
+func (c Client) abortMultipartUpload(bucket, key string, uploadID *string) {
+	c.s3.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   &bucket,
+		Key:      &key,
+		UploadId: uploadID,
+	})
+}
+
+// copyPartSize returns the size of each part when copying an object of the size provided, which is increased as
+// necessary to keep the number of parts within maxUploadParts.
+func copyPartSize(size int64) int64 {
+	partSize := int64(defaultCopyPartSize)
+	if min := (size + maxUploadParts - 1) / maxUploadParts; partSize < min {
+		partSize = min
+	}
+
+	return partSize
+}
+
+// copySource returns the URL-encoded source of a copy request for an object.
+func copySource(bucket, key string) string {
+	return strings.Replace(url.QueryEscape(bucket+pathDelimiter+key), "+", "%20", -1)
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestClient_CopyObject(t *testing.T) {
+	// Single request
+	{
+		var mockS3 mockS3Communicator
+		mockS3.headObjectCallback = func(i *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			if *i.Bucket != "src" || *i.Key != "folder/a b.txt" {
+				t.Fatalf("Unexpected HeadObjectInput: %v", i)
+			}
+			return &s3.HeadObjectOutput{ContentLength: aws.Int64(maxCopySize)}, nil
+		}
+		mockS3.copyObjectCallback = func(i *s3.CopyObjectInput) (*s3.CopyObjectOutput, error) {
+			if *i.Bucket != "dst" || *i.Key != "b.txt" || *i.CopySource != "src%2Ffolder%2Fa%20b.txt" {
+				t.Fatalf("Unexpected CopyObjectInput: %v", i)
+			}
+			return nil, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.CopyObject("src", "folder/a b.txt", "dst", "b.txt"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Multipart
+	{
+		size := int64(maxCopySize + 1)
+		var ranges []string
+		var completed bool
+
+		var mockS3 mockS3Communicator
+		mockS3.headObjectCallback = func(i *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return &s3.HeadObjectOutput{
+				ContentLength:        &size,
+				ContentType:          aws.String("text/plain"),
+				ContentEncoding:      aws.String("gzip"),
+				CacheControl:         aws.String("max-age=60"),
+				ContentDisposition:   aws.String("attachment"),
+				Expires:              aws.String("Wed, 21 Oct 2015 07:28:00 GMT"),
+				ServerSideEncryption: aws.String("aws:kms"),
+				SSEKMSKeyId:          aws.String("key"),
+			}, nil
+		}
+		mockS3.copyObjectCallback = func(i *s3.CopyObjectInput) (*s3.CopyObjectOutput, error) {
+			t.Fatal("CopyObject should not be used for objects larger than maxCopySize")
+			return nil, nil
+		}
+		mockS3.createMultipartUploadCallback = func(i *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+			if *i.Bucket != "dst" || *i.Key != "b.txt" || *i.ContentType != "text/plain" {
+				t.Fatalf("Unexpected CreateMultipartUploadInput: %v", i)
+			} else if aws.StringValue(i.ContentEncoding) != "gzip" || aws.StringValue(i.CacheControl) != "max-age=60" ||
+				aws.StringValue(i.ContentDisposition) != "attachment" || i.Expires == nil || i.Expires.Year() != 2015 {
+				t.Fatalf("Expected the headers of the source object to be copied: %v", i)
+			} else if aws.StringValue(i.ServerSideEncryption) != "aws:kms" || aws.StringValue(i.SSEKMSKeyId) != "key" {
+				t.Fatalf("Expected the encryption of the source object to be copied: %v", i)
+			}
+			return &s3.CreateMultipartUploadOutput{UploadId: aws.String("id")}, nil
+		}
+		mockS3.uploadPartCopyCallback = func(i *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error) {
+			if *i.UploadId != "id" || *i.PartNumber != int64(len(ranges)+1) {
+				t.Fatalf("Unexpected UploadPartCopyInput: %v", i)
+			}
+			ranges = append(ranges, *i.CopySourceRange)
+			return &s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: aws.String("etag")}}, nil
+		}
+		mockS3.completeMultipartUploadCallback = func(i *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+			if len(i.MultipartUpload.Parts) != len(ranges) {
+				t.Fatalf("Unexpected parts completed: %v", i.MultipartUpload.Parts)
+			}
+			completed = true
+			return nil, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.CopyObject("src", "a.txt", "dst", "b.txt"); err != nil {
+			t.Fatal(err)
+		}
+
+		expectedParts := int(size/defaultCopyPartSize) + 1
+		if len(ranges) != expectedParts || !completed {
+			t.Fatalf("Unexpected parts copied: %v", ranges)
+		} else if ranges[0] != fmt.Sprintf("bytes=0-%d", defaultCopyPartSize-1) || ranges[len(ranges)-1] != fmt.Sprintf("bytes=%d-%d", size-1, size-1) {
+			t.Fatalf("Unexpected part ranges: %v", ranges)
+		}
+	}
+
+	// Multipart error
+	{
+		mockErr := errors.New("Mock Error")
+		var aborted bool
+
+		var mockS3 mockS3Communicator
+		mockS3.headObjectCallback = func(i *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return &s3.HeadObjectOutput{ContentLength: aws.Int64(maxCopySize + 1)}, nil
+		}
+		mockS3.createMultipartUploadCallback = func(i *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+			return &s3.CreateMultipartUploadOutput{UploadId: aws.String("id")}, nil
+		}
+		mockS3.uploadPartCopyCallback = func(i *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error) {
+			return nil, mockErr
+		}
+		mockS3.abortMultipartUploadCallback = func(i *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error) {
+			if *i.UploadId != "id" {
+				t.Fatalf("Unexpected AbortMultipartUploadInput: %v", i)
+			}
+			aborted = true
+			return nil, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.CopyObject("src", "a.txt", "dst", "b.txt"); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		} else if !aborted {
+			t.Fatal("Expected multipart upload to be aborted after an error")
+		}
+	}
+
+	// Head error
+	{
+		mockErr := errors.New("Mock Error")
+
+		var mockS3 mockS3Communicator
+		mockS3.headObjectCallback = func(i *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return nil, mockErr
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.CopyObject("src", "a.txt", "dst", "b.txt"); err != mockErr {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
+
+func TestCopyPartSize(t *testing.T) {
+	const maxObjectSize = 5 * 1024 * 1024 * 1024 * 1024
+
+	tests := []struct {
+		size     int64
+		expected int64
+	}{
+		{maxCopySize + 1, defaultCopyPartSize},
+		{defaultCopyPartSize * maxUploadParts, defaultCopyPartSize},
+		{defaultCopyPartSize*maxUploadParts + 1, defaultCopyPartSize + 1},
+		{maxObjectSize, (maxObjectSize + maxUploadParts - 1) / maxUploadParts},
+	}
+
+	for _, test := range tests {
+		partSize := copyPartSize(test.size)
+		if partSize != test.expected {
+			t.Fatalf("Unexpected part size for %v: {Expected: %v, Actual: %v}", test.size, test.expected, partSize)
+		} else if parts := (test.size + partSize - 1) / partSize; parts > maxUploadParts {
+			t.Fatalf("Too many parts for %v: %v", test.size, parts)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// PartialSuffix is appended to the path of a file while it is being downloaded.
+	PartialSuffix = ".s3fs-partial"
+
+	// DefaultDownloadConcurrency is the number of ranges of a large object downloaded at once, unless otherwise
+	// provided.
+	DefaultDownloadConcurrency = 4
+
+	// errCodePreconditionFailed is the error code returned when an object no longer matches the ETag of a
+	// conditional request.
+	errCodePreconditionFailed = "PreconditionFailed"
+)
+
+// ErrDownloadInterrupted is returned when a download is cancelled before it completes.
+var ErrDownloadInterrupted = errors.New("Download interrupted")
+
+// DownloadOptions configures how objects are downloaded, where the zero value uses the default part size and
+// concurrency.
+type DownloadOptions struct {
+	// PartSize is the size of each byte range of a large object. Objects larger than a single part are downloaded
+	// in ranges.
+	PartSize int64
+
+	// Concurrency is the number of ranges of a large object downloaded at once.
+	Concurrency int
+
+	// Cancel interrupts the download when closed. The partial download is kept in the journal to be resumed, or
+	// removed if the client has no journal.
+	Cancel <-chan struct{}
+}
+
+// DownloadFile downloads an object to the local file at path, replacing any existing file once the download
+// completes.
+//
+// Objects larger than the part size are split into byte ranges that are downloaded concurrently and written at
+// their offsets within the file. The object is written to a partial file alongside path until it has been
+// downloaded. If the download fails or is cancelled, its progress is recorded in the journal, and downloading the
+// same object to the same path resumes from where it stopped, provided the object has not changed.
+func (c Client) DownloadFile(bucket, key, path string, opts DownloadOptions) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	partial := path + PartialSuffix
+
+	t, err := c.startDownload(bucket, key, path, partial)
+	if err != nil {
+		return err
+	}
+	resumed := t.Offset > 0 || t.PartSize > 0
+
+	err = c.download(&t, partial, opts)
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == errCodePreconditionFailed && resumed {
+		// The object has changed since the download started, so it must be downloaded again from the start.
+		if err := c.discardDownload(t); err != nil {
+			return err
+		}
+		return c.DownloadFile(bucket, key, path, opts)
+	} else if err != nil {
+		// Keep the download to be resumed, unless nothing was downloaded.
+		if c.journal == nil || len(t.ETag) == 0 {
+			os.Remove(partial)
+		} else {
+			c.journal.save(t)
+		}
+		return err
+	}
+
+	if err := os.Rename(partial, path); err != nil {
+		return err
+	}
+
+	if c.journal != nil {
+		return c.journal.remove(t)
+	}
+
+	return nil
+}
+
+// download writes an object to the partial file, resuming from the progress of the transfer, which is updated
+// as the download proceeds.
+//
+// A new download begins with a single request for the entire object. If the object is larger than the part size,
+// the first part is read from that request while the remaining parts are requested as byte ranges.
+func (c Client) download(t *Transfer, partial string, opts DownloadOptions) error {
+	if t.PartSize > 0 {
+		file, err := os.OpenFile(partial, os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+
+		return c.downloadRanges(t, file, nil, opts)
+	}
+
+	input := s3.GetObjectInput{
+		Bucket: &t.Bucket,
+		Key:    &t.Key,
+	}
+	if t.Offset > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", t.Offset))
+		input.IfMatch = aws.String(t.ETag)
+	}
+
+	output, err := c.s3.GetObject(&input)
+	if err != nil {
+		return err
+	}
+	defer output.Body.Close()
+
+	flag := os.O_WRONLY | os.O_APPEND
+	if t.Offset == 0 {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		t.Size = aws.Int64Value(output.ContentLength)
+		t.ETag = aws.StringValue(output.ETag)
+	}
+
+	file, err := os.OpenFile(partial, flag, 0644)
+	if err != nil {
+		return err
+	}
+
+	if partSize := opts.partSize(); t.Offset == 0 && t.Size > partSize {
+		t.PartSize = partSize
+		if err := file.Truncate(t.Size); err != nil {
+			file.Close()
+			return err
+		}
+
+		return c.downloadRanges(t, file, output.Body, opts)
+	}
+
+	if c.journal != nil {
+		if err := c.journal.save(*t); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	n, err := io.Copy(file, cancelReader{r: output.Body, cancel: opts.Cancel})
+	t.Offset += n
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// downloadRanges downloads each part of an object that hasn't already been downloaded, with up to the configured
+// concurrency of parts downloaded at once, and writes them at their offsets within file, which is closed once
+// the parts are downloaded. If first is provided, the first part is read from it rather than requested.
+//
+// Each part is recorded in the journal as it completes. If any part fails to download, or the download is
+// cancelled, no further parts are downloaded.
+func (c Client) downloadRanges(t *Transfer, file *os.File, first io.ReadCloser, opts DownloadOptions) error {
+	bucket, key, etag, size, partSize := t.Bucket, t.Key, t.ETag, t.Size, t.PartSize
+
+	done := make([]bool, (size+partSize-1)/partSize)
+	for _, p := range t.Parts {
+		if p.Number >= 1 && p.Number <= int64(len(done)) {
+			done[p.Number-1] = true
+		}
+	}
+
+	if c.journal != nil {
+		if err := c.journal.save(*t); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	// stop is closed by the first failure, or cancellation, to prevent any further parts from being downloaded.
+	stop := make(chan struct{})
+	var once sync.Once
+	var downloadErr error
+	fail := func(err error) {
+		once.Do(func() {
+			downloadErr = err
+			close(stop)
+		})
+	}
+
+	// record adds a completed part to the journal, so that it isn't downloaded again if the download is resumed.
+	var mu sync.Mutex
+	record := func(num int) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		t.Parts = append(t.Parts, TransferPart{Number: int64(num + 1)})
+		if c.journal == nil {
+			return nil
+		}
+		return c.journal.save(*t)
+	}
+
+	nums := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.concurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for num := range nums {
+				select {
+				case <-stop:
+					continue
+				case <-opts.Cancel:
+					fail(ErrDownloadInterrupted)
+					continue
+				default:
+				}
+
+				offset := int64(num) * partSize
+				length := partSize
+				if offset+length > size {
+					length = size - offset
+				}
+
+				var body io.ReadCloser
+				if num == 0 {
+					body = first
+				}
+
+				if err := c.downloadRange(bucket, key, etag, file, offset, length, body, opts.Cancel); err != nil {
+					fail(err)
+				} else if err := record(num); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+queue:
+	for num := range done {
+		if done[num] {
+			continue
+		}
+
+		select {
+		case nums <- num:
+		case <-stop:
+			break queue
+		}
+	}
+	close(nums)
+	wg.Wait()
+
+	if err := file.Close(); downloadErr == nil {
+		downloadErr = err
+	}
+
+	return downloadErr
+}
+
+// downloadRange downloads length bytes of an object starting at offset, provided it still has the ETag given, and
+// writes them at the same offset within file. If body is provided, the range is read from it, and then closed,
+// rather than requested.
+func (c Client) downloadRange(bucket, key, etag string, file *os.File, offset, length int64, body io.ReadCloser, cancel <-chan struct{}) error {
+	if body == nil {
+		output, err := c.s3.GetObject(&s3.GetObjectInput{
+			Bucket:  &bucket,
+			Key:     &key,
+			Range:   aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+			IfMatch: &etag,
+		})
+		if err != nil {
+			return err
+		}
+		body = output.Body
+	}
+	defer body.Close()
+
+	n, err := io.Copy(&offsetWriter{file: file, offset: offset}, cancelReader{r: io.LimitReader(body, length), cancel: cancel})
+	if err == nil && n != length {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// startDownload returns the download of an object to path, resuming the download recorded in the journal if its
+// partial file remains, or starting a new download otherwise.
+func (c Client) startDownload(bucket, key, path, partial string) (Transfer, error) {
+	t := Transfer{
+		Kind:    TransferDownload,
+		Bucket:  bucket,
+		Key:     key,
+		Path:    path,
+		Started: time.Now(),
+	}
+	if c.journal == nil {
+		return t, nil
+	}
+
+	recorded, ok, err := c.journal.find(TransferDownload, bucket, key, path)
+	if err != nil || !ok {
+		return t, err
+	}
+
+	info, err := os.Stat(partial)
+	switch {
+	case err != nil:
+
+	// A download in ranges resumes with the parts that remain, within a partial file of the full size.
+	case recorded.PartSize > 0 && info.Size() == recorded.Size:
+		return recorded, nil
+
+	// Otherwise, resume from the end of the partial file, which may have been written beyond the recorded offset
+	// before the download stopped. A complete partial file is downloaded again, as no range remains to verify it
+	// with.
+	case recorded.PartSize == 0 && info.Size() > 0 && info.Size() < recorded.Size:
+		recorded.Offset = info.Size()
+		return recorded, nil
+	}
+
+	return t, c.discardDownload(recorded)
+}
+
+// discardDownload removes the partial file and journal record of a download.
+func (c Client) discardDownload(t Transfer) error {
+	if err := os.Remove(t.Path + PartialSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if c.journal != nil {
+		return c.journal.remove(t)
+	}
+
+	return nil
+}
+
+// Transfers returns the uploads and downloads that stopped before completing, and can be resumed.
+func (c Client) Transfers() ([]Transfer, error) {
+	if c.journal == nil {
+		return nil, nil
+	}
+
+	return c.journal.Transfers()
+}
+
+// DiscardTransfer abandons a transfer that stopped before completing, aborting the multipart upload or removing
+// the partial file of the download.
+func (c Client) DiscardTransfer(t Transfer) error {
+	if t.Kind == TransferUpload {
+		c.abortMultipartUpload(t.Bucket, t.Key, &t.UploadID)
+		if c.journal != nil {
+			return c.journal.remove(t)
+		}
+		return nil
+	}
+
+	return c.discardDownload(t)
+}
+
+// partSize returns the size of each byte range of a large object, within the limits of a multipart upload so
+// that ranges are never unreasonably small.
+func (o DownloadOptions) partSize() int64 {
+	return clampPartSize(o.PartSize)
+}
+
+// concurrency returns the number of ranges to download at once.
+func (o DownloadOptions) concurrency() int {
+	if o.Concurrency <= 0 {
+		return DefaultDownloadConcurrency
+	}
+
+	return o.Concurrency
+}
+
+// offsetWriter writes sequentially to a file, starting at an offset, so that ranges of a file may be written
+// concurrently.
+type offsetWriter struct {
+	file   *os.File
+	offset int64
+}
+
+// Write writes to the file at the current offset, and advances the offset.
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, err := w.file.WriteAt(p, w.offset)
+	w.offset += int64(n)
+	return n, err
+}
+
+// cancelReader is a reader that stops with ErrDownloadInterrupted once cancel is closed.
+type cancelReader struct {
+	r      io.Reader
+	cancel <-chan struct{}
+}
+
+// Read reads from the underlying reader, unless cancelled.
+func (c cancelReader) Read(p []byte) (int, error) {
+	select {
+	case <-c.cancel:
+		return 0, ErrDownloadInterrupted
+	default:
+	}
+
+	return c.r.Read(p)
+}
@@ -0,0 +1,350 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestClient_DownloadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "file.txt")
+	data := "0123456789"
+
+	// getObject returns a callback that serves the range of data requested, while it has the ETag provided.
+	getObject := func(etag string) func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+		return func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			if *i.Bucket != "bucket" || *i.Key != "key" {
+				t.Fatalf("Unexpected GetObjectInput: %v", i)
+			}
+
+			body := data
+			if i.Range != nil {
+				if i.IfMatch == nil {
+					t.Fatal("Expected a ranged request to match the ETag")
+				} else if *i.IfMatch != etag {
+					return nil, awserr.New(errCodePreconditionFailed, "Mock Error", nil)
+				}
+
+				var start int
+				if _, err := fmt.Sscanf(*i.Range, "bytes=%d-", &start); err != nil {
+					t.Fatal(err)
+				}
+				body = data[start:]
+			}
+
+			return &s3.GetObjectOutput{
+				Body:          &mockReadCloser{data: []byte(body)},
+				ContentLength: aws.Int64(int64(len(body))),
+				ETag:          aws.String(etag),
+			}, nil
+		}
+	}
+
+	// Positive case
+	{
+		var mockS3 mockS3Communicator
+		mockS3.getObjectCallback = getObject("etag")
+
+		c := Client{s3: &mockS3}
+		if err := c.DownloadFile("bucket", "key", path, DownloadOptions{}); err != nil {
+			t.Fatal(err)
+		} else if b, _ := ioutil.ReadFile(path); string(b) != data {
+			t.Fatalf("Unexpected file contents: {Expected: %v, Actual: %v}", data, string(b))
+		} else if _, err := os.Stat(path + PartialSuffix); !os.IsNotExist(err) {
+			t.Fatalf("Expected partial file to be removed: %v", err)
+		}
+	}
+
+	// S3 error, without a journal, leaves no partial file behind
+	{
+		mockErr := errors.New("Mock Error")
+
+		var mockS3 mockS3Communicator
+		mockS3.getObjectCallback = func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			return &s3.GetObjectOutput{Body: &mockReadCloser{err: mockErr}}, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.DownloadFile("bucket", "key", path+".err", DownloadOptions{}); err != mockErr {
+			t.Fatalf("Expected mock error to be returned: %v", err)
+		} else if _, err := os.Stat(path + ".err" + PartialSuffix); !os.IsNotExist(err) {
+			t.Fatalf("Expected partial file to be removed: %v", err)
+		}
+	}
+
+	// With a journal, a partial download is resumed from the end of the partial file
+	for _, changed := range []bool{false, true} {
+		j := NewJournal(filepath.Join(dir, "journal"))
+		j.save(Transfer{Kind: TransferDownload, Bucket: "bucket", Key: "key", Path: path, Size: 10, ETag: "etag", Offset: 2})
+		if err := ioutil.WriteFile(path+PartialSuffix, []byte("0123"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var requests []string
+		get := getObject("etag")
+		if changed {
+			get = getObject("changed")
+		}
+
+		var mockS3 mockS3Communicator
+		mockS3.getObjectCallback = func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			requests = append(requests, aws.StringValue(i.Range))
+			return get(i)
+		}
+
+		c := Client{s3: &mockS3, journal: j}
+		if err := c.DownloadFile("bucket", "key", path, DownloadOptions{}); err != nil {
+			t.Fatal(err)
+		} else if b, _ := ioutil.ReadFile(path); string(b) != data {
+			t.Fatalf("Unexpected file contents: {Expected: %v, Actual: %v}", data, string(b))
+		} else if transfers, err := j.Transfers(); err != nil || len(transfers) != 0 {
+			t.Fatalf("Expected the completed download to be removed from the journal: %v, %v", transfers, err)
+		}
+
+		// A changed object is downloaded again from the start.
+		expected := []string{"bytes=4-"}
+		if changed {
+			expected = []string{"bytes=4-", ""}
+		}
+		if len(requests) != len(expected) || requests[0] != expected[0] || requests[len(requests)-1] != expected[len(expected)-1] {
+			t.Fatalf("Unexpected requests: {Expected: %v, Actual: %v}", expected, requests)
+		}
+	}
+
+	// With a journal, an interrupted download is kept to be resumed
+	{
+		j := NewJournal(filepath.Join(dir, "journal"))
+		cancel := make(chan struct{})
+
+		var mockS3 mockS3Communicator
+		mockS3.getObjectCallback = func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			close(cancel)
+			return &s3.GetObjectOutput{
+				Body:          &mockReadCloser{data: []byte(data)},
+				ContentLength: aws.Int64(int64(len(data))),
+				ETag:          aws.String("etag"),
+			}, nil
+		}
+
+		c := Client{s3: &mockS3, journal: j}
+		if err := c.DownloadFile("bucket", "key", path+".cancel", DownloadOptions{Cancel: cancel}); err != ErrDownloadInterrupted {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+
+		transfers, err := j.Transfers()
+		if err != nil {
+			t.Fatal(err)
+		} else if len(transfers) != 1 || transfers[0].Kind != TransferDownload || transfers[0].Size != 10 || transfers[0].ETag != "etag" {
+			t.Fatalf("Unexpected transfers recorded: %v", transfers)
+		} else if _, err := os.Stat(path + ".cancel" + PartialSuffix); err != nil {
+			t.Fatalf("Expected partial file to be kept: %v", err)
+		}
+
+		// Discard
+		if err := c.DiscardTransfer(transfers[0]); err != nil {
+			t.Fatal(err)
+		} else if _, err := os.Stat(path + ".cancel" + PartialSuffix); !os.IsNotExist(err) {
+			t.Fatalf("Expected partial file to be removed: %v", err)
+		} else if transfers, err := c.Transfers(); err != nil || len(transfers) != 0 {
+			t.Fatalf("Expected the discarded download to be removed from the journal: %v, %v", transfers, err)
+		}
+	}
+}
+
+func TestClient_DiscardTransfer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	j := NewJournal(dir)
+
+	upload := Transfer{Kind: TransferUpload, Bucket: "bucket", Key: "key", Path: "/file", UploadID: "id"}
+	j.save(upload)
+
+	var aborted bool
+	var mockS3 mockS3Communicator
+	mockS3.abortMultipartUploadCallback = func(i *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error) {
+		if *i.Bucket != "bucket" || *i.Key != "key" || *i.UploadId != "id" {
+			t.Fatalf("Unexpected AbortMultipartUploadInput: %v", i)
+		}
+		aborted = true
+		return nil, nil
+	}
+
+	c := Client{s3: &mockS3, journal: j}
+	if err := c.DiscardTransfer(upload); err != nil {
+		t.Fatal(err)
+	} else if !aborted {
+		t.Fatal("Expected the multipart upload to be aborted")
+	} else if transfers, err := c.Transfers(); err != nil || len(transfers) != 0 {
+		t.Fatalf("Expected the discarded upload to be removed from the journal: %v, %v", transfers, err)
+	}
+}
+
+func TestClient_DownloadFile_ranges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "file.bin")
+
+	// Create an object of two full parts and a partial part, where each byte differs from its neighbours.
+	data := make([]byte, MinPartSize*2+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	lastRange := fmt.Sprintf("bytes=%d-%d", MinPartSize*2, len(data)-1)
+
+	// mockS3 returns a communicator that serves the ranges requested, while the object has the ETag provided, and
+	// records each range, failing those provided.
+	mockS3 := func(etag string, requests *[]string, fails ...string) *mockS3Communicator {
+		var mu sync.Mutex
+		var m mockS3Communicator
+		m.getObjectCallback = func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			mu.Lock()
+			*requests = append(*requests, aws.StringValue(i.Range))
+			mu.Unlock()
+
+			if i.Range == nil {
+				return &s3.GetObjectOutput{
+					Body:          &mockReadCloser{data: data},
+					ContentLength: aws.Int64(int64(len(data))),
+					ETag:          aws.String(etag),
+				}, nil
+			}
+
+			if aws.StringValue(i.IfMatch) != etag {
+				return nil, awserr.New(errCodePreconditionFailed, "Mock Error", nil)
+			}
+			for _, f := range fails {
+				if *i.Range == f {
+					return nil, errors.New("Mock Error")
+				}
+			}
+
+			var start, end int
+			if _, err := fmt.Sscanf(*i.Range, "bytes=%d-%d", &start, &end); err != nil {
+				t.Fatal(err)
+			}
+			return &s3.GetObjectOutput{
+				Body:          &mockReadCloser{data: data[start : end+1]},
+				ContentLength: aws.Int64(int64(end - start + 1)),
+				ETag:          aws.String(etag),
+			}, nil
+		}
+		return &m
+	}
+
+	// validate ensures the file was downloaded with the requests expected, in any order.
+	validate := func(requests []string, expected ...string) {
+		sort.Strings(requests)
+		sort.Strings(expected)
+		if strings.Join(requests, ",") != strings.Join(expected, ",") {
+			t.Fatalf("Unexpected requests: {Expected: %v, Actual: %v}", expected, requests)
+		}
+
+		if b, err := ioutil.ReadFile(path); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(b, data) {
+			t.Fatal("Downloaded file contents incorrect!")
+		}
+	}
+
+	opts := DownloadOptions{PartSize: MinPartSize, Concurrency: 2}
+
+	// Positive case, where the first part is read from the initial request
+	{
+		var requests []string
+		c := Client{s3: mockS3("etag", &requests)}
+		if err := c.DownloadFile("bucket", "key", path, opts); err != nil {
+			t.Fatal(err)
+		}
+		validate(requests, "", fmt.Sprintf("bytes=%d-%d", MinPartSize, MinPartSize*2-1), lastRange)
+	}
+
+	// With a journal, a failed download is kept and then resumed with only the remaining parts
+	{
+		j := NewJournal(filepath.Join(dir, "journal"))
+
+		var requests []string
+		c := Client{s3: mockS3("etag", &requests, lastRange), journal: j}
+		if err := c.DownloadFile("bucket", "key", path+".2", DownloadOptions{PartSize: MinPartSize, Concurrency: 1}); err == nil {
+			t.Fatal("Expected error to be returned")
+		}
+
+		transfers, err := j.Transfers()
+		if err != nil {
+			t.Fatal(err)
+		} else if len(transfers) != 1 || transfers[0].PartSize != MinPartSize || transfers[0].Size != int64(len(data)) ||
+			len(transfers[0].Parts) != 2 {
+			t.Fatalf("Unexpected transfers recorded: %v", transfers)
+		}
+
+		requests = nil
+		c = Client{s3: mockS3("etag", &requests), journal: j}
+		if err := c.DownloadFile("bucket", "key", path+".2", opts); err != nil {
+			t.Fatal(err)
+		}
+		os.Rename(path+".2", path)
+		validate(requests, lastRange)
+
+		if transfers, err := j.Transfers(); err != nil || len(transfers) != 0 {
+			t.Fatalf("Expected the completed download to be removed from the journal: %v, %v", transfers, err)
+		}
+	}
+
+	// With a journal, a download of an object that has since changed is started again
+	{
+		j := NewJournal(filepath.Join(dir, "journal"))
+
+		var requests []string
+		c := Client{s3: mockS3("etag", &requests, lastRange), journal: j}
+		if err := c.DownloadFile("bucket", "key", path, DownloadOptions{PartSize: MinPartSize, Concurrency: 1}); err == nil {
+			t.Fatal("Expected error to be returned")
+		}
+
+		requests = nil
+		c = Client{s3: mockS3("changed", &requests), journal: j}
+		if err := c.DownloadFile("bucket", "key", path, opts); err != nil {
+			t.Fatal(err)
+		}
+		validate(requests, lastRange, "", fmt.Sprintf("bytes=%d-%d", MinPartSize, MinPartSize*2-1), lastRange)
+	}
+}
+
+func TestDownloadOptions_partSize(t *testing.T) {
+	tests := []struct {
+		partSize int64
+		expected int64
+	}{
+		{0, DefaultPartSize},
+		{1, MinPartSize},
+		{MinPartSize * 2, MinPartSize * 2},
+		{MaxPartSize * 2, MaxPartSize},
+	}
+
+	for _, test := range tests {
+		opts := DownloadOptions{PartSize: test.partSize}
+		if actual := opts.partSize(); actual != test.expected {
+			t.Fatalf("Unexpected part size for %v: {Expected: %v, Actual: %v}", test.partSize, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TransferUpload is the kind of a multipart upload recorded by a Journal.
+	TransferUpload = "upload"
+
+	// TransferDownload is the kind of a download recorded by a Journal.
+	TransferDownload = "download"
+
+	// journalExtension is the extension of each transfer file within a journal directory.
+	journalExtension = ".json"
+)
+
+// Transfer is the recorded progress of an upload or download, from which it can be resumed.
+type Transfer struct {
+	Kind    string
+	Bucket  string
+	Key     string
+	Path    string
+	Size    int64
+	Started time.Time
+
+	// ModTime is the modification time of the local file being uploaded, used to detect changes to the file.
+	ModTime time.Time
+
+	// UploadID records the multipart upload of an upload.
+	UploadID string
+
+	// PartSize and Parts record the parts completed for an upload, or for a download performed in ranges.
+	PartSize int64
+	Parts    []TransferPart
+
+	// ETag is the ETag of the object being downloaded, used to detect changes to the object, and Offset is the
+	// number of bytes downloaded when a download that isn't performed in ranges stopped.
+	ETag   string
+	Offset int64
+}
+
+// TransferPart is a completed part of a multipart upload, or a completed range of a download, where the ETag is
+// only recorded for uploads.
+type TransferPart struct {
+	Number int64
+	ETag   string
+}
+
+// Journal records the progress of transfers as files within a local state directory, so that interrupted
+// transfers can be resumed.
+type Journal struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewJournal returns a Journal that stores transfers within dir, which is created when first required.
+func NewJournal(dir string) *Journal {
+	return &Journal{dir: dir}
+}
+
+// Transfers returns every transfer recorded in the journal, ordered by when they were started.
+func (j *Journal) Transfers() ([]Transfer, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	files, err := ioutil.ReadDir(j.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var transfers []Transfer
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), journalExtension) {
+			continue
+		}
+
+		t, err := j.read(filepath.Join(j.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+
+	sort.Sort(byStarted(transfers))
+	return transfers, nil
+}
+
+// find returns the transfer of a kind recorded for an object and local file, if there is one.
+func (j *Journal) find(kind, bucket, key, path string) (Transfer, bool, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	t, err := j.read(j.path(Transfer{Kind: kind, Bucket: bucket, Key: key, Path: path}))
+	if os.IsNotExist(err) {
+		return t, false, nil
+	} else if err != nil {
+		return t, false, err
+	}
+
+	return t, true, nil
+}
+
+// save records a transfer, replacing any previous record of it.
+//
+// The record is written to a temporary file first, so that an interruption never leaves a partial record.
+func (j *Journal) save(t Transfer) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	} else if err := os.MkdirAll(j.dir, 0700); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(j.dir, "")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	} else if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), j.path(t))
+}
+
+// remove deletes the record of a transfer, if it exists.
+func (j *Journal) remove(t Transfer) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := os.Remove(j.path(t)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// read reads a transfer from the record at path.
+func (j *Journal) read(path string) (Transfer, error) {
+	var t Transfer
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return t, err
+	}
+
+	err = json.Unmarshal(b, &t)
+	return t, err
+}
+
+// path returns the location of the record of a transfer, which is named for its kind, object and local file.
+func (j *Journal) path(t Transfer) string {
+	sum := sha1.Sum([]byte(strings.Join([]string{t.Kind, t.Bucket, t.Key, t.Path}, "\x00")))
+	return filepath.Join(j.dir, hex.EncodeToString(sum[:])+journalExtension)
+}
+
+// byStarted sorts transfers by when they were started.
+type byStarted []Transfer
+
+func (t byStarted) Len() int           { return len(t) }
+func (t byStarted) Less(i, j int) bool { return t[i].Started.Before(t[j].Started) }
+func (t byStarted) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The directory is only created once a transfer is recorded.
+	j := NewJournal(filepath.Join(dir, "transfers"))
+	if transfers, err := j.Transfers(); err != nil || len(transfers) != 0 {
+		t.Fatalf("Unexpected transfers in an empty journal: %v, %v", transfers, err)
+	}
+
+	now := time.Now()
+	upload := Transfer{Kind: TransferUpload, Bucket: "bucket", Key: "key", Path: "/file", Started: now, UploadID: "id"}
+	download := Transfer{Kind: TransferDownload, Bucket: "bucket", Key: "key", Path: "/file", Started: now.Add(-time.Minute)}
+	for _, tr := range []Transfer{upload, download} {
+		if err := j.save(tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Saving again replaces the existing record.
+	upload.Parts = []TransferPart{{Number: 1, ETag: "etag"}}
+	if err := j.save(upload); err != nil {
+		t.Fatal(err)
+	}
+
+	transfers, err := j.Transfers()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(transfers) != 2 || transfers[0].Kind != TransferDownload || transfers[1].Kind != TransferUpload {
+		t.Fatalf("Unexpected transfers, expected to be ordered by start time: %v", transfers)
+	} else if len(transfers[1].Parts) != 1 || transfers[1].Parts[0].ETag != "etag" {
+		t.Fatalf("Unexpected parts recorded: %v", transfers[1].Parts)
+	}
+
+	// Find
+	if tr, ok, err := j.find(TransferUpload, "bucket", "key", "/file"); err != nil || !ok || tr.UploadID != "id" {
+		t.Fatalf("Unexpected transfer found: %v, %v, %v", tr, ok, err)
+	} else if _, ok, err := j.find(TransferUpload, "bucket", "other", "/file"); err != nil || ok {
+		t.Fatalf("Unexpected transfer found for another key: %v, %v", ok, err)
+	}
+
+	// Remove, where removing a transfer that isn't recorded is not an error.
+	for i := 0; i < 2; i++ {
+		if err := j.remove(upload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if transfers, err := j.Transfers(); err != nil || len(transfers) != 1 || transfers[0].Kind != TransferDownload {
+		t.Fatalf("Unexpected transfers after removal: %v, %v", transfers, err)
+	}
+}
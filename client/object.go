@@ -0,0 +1,126 @@
+package client
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// Object represents an Amazon S3 object, along with the metadata returned when listing it.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	StorageClass string
+	ETag         string
+	Owner        string
+}
+
+// newObject converts an object returned by the S3 API into an Object, safely handling any missing fields.
+func newObject(o *s3.Object) Object {
+	var obj Object
+
+	if o.Key != nil {
+		obj.Key = *o.Key
+	}
+	if o.Size != nil {
+		obj.Size = *o.Size
+	}
+	if o.LastModified != nil {
+		obj.LastModified = *o.LastModified
+	}
+	if o.StorageClass != nil {
+		obj.StorageClass = *o.StorageClass
+	}
+	if o.ETag != nil {
+		obj.ETag = *o.ETag
+	}
+
+	// Prefer the human-friendly display name of the owner, falling back to their ID.
+	if o.Owner != nil {
+		if o.Owner.DisplayName != nil && len(*o.Owner.DisplayName) > 0 {
+			obj.Owner = *o.Owner.DisplayName
+		} else if o.Owner.ID != nil {
+			obj.Owner = *o.Owner.ID
+		}
+	}
+
+	return obj
+}
+
+// ObjectInfo represents the full metadata of an Amazon S3 object, as returned by a HEAD request.
+type ObjectInfo struct {
+	Key                  string
+	Size                 int64
+	ContentType          string
+	ETag                 string
+	LastModified         time.Time
+	StorageClass         string
+	ServerSideEncryption string
+	KMSKeyID             string
+	VersionID            string
+	Restore              string
+
+	// Metadata contains the user-defined metadata of the object, keyed by name without the x-amz-meta- prefix.
+	Metadata map[string]string
+}
+
+// newObjectInfo converts the response of a HEAD object request into an ObjectInfo, safely handling any missing
+// fields.
+//
+// Amazon S3 omits the storage class of objects in the standard storage class.
+func newObjectInfo(key string, o *s3.HeadObjectOutput) ObjectInfo {
+	info := ObjectInfo{
+		Key:                  key,
+		Size:                 aws.Int64Value(o.ContentLength),
+		ContentType:          aws.StringValue(o.ContentType),
+		ETag:                 aws.StringValue(o.ETag),
+		LastModified:         aws.TimeValue(o.LastModified),
+		StorageClass:         aws.StringValue(o.StorageClass),
+		ServerSideEncryption: aws.StringValue(o.ServerSideEncryption),
+		KMSKeyID:             aws.StringValue(o.SSEKMSKeyId),
+		VersionID:            aws.StringValue(o.VersionId),
+		Restore:              aws.StringValue(o.Restore),
+		Metadata:             make(map[string]string, len(o.Metadata)),
+	}
+
+	if len(info.StorageClass) == 0 {
+		info.StorageClass = s3.StorageClassStandard
+	}
+
+	for k, v := range o.Metadata {
+		info.Metadata[strings.ToLower(k)] = aws.StringValue(v)
+	}
+
+	return info
+}
+
+// ObjectVersion identifies a single version of an Amazon S3 object, or a delete marker.
+type ObjectVersion struct {
+	Key            string
+	VersionID      string
+	IsDeleteMarker bool
+}
+
+// DeleteError describes an object that could not be deleted.
+type DeleteError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+// Error returns a description of the failed deletion.
+func (e DeleteError) Error() string {
+	return e.Key + ": " + e.Message + " (" + e.Code + ")"
+}
+
+// newDeleteError converts an error returned by the S3 API when deleting multiple objects into a DeleteError.
+func newDeleteError(e *s3.Error) DeleteError {
+	return DeleteError{
+		Key:     aws.StringValue(e.Key),
+		Code:    aws.StringValue(e.Code),
+		Message: aws.StringValue(e.Message),
+	}
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func Test_newObject(t *testing.T) {
+	// All fields
+	{
+		now := time.Now()
+		o := newObject(&s3.Object{
+			Key:          aws.String("key"),
+			Size:         aws.Int64(10),
+			LastModified: &now,
+			StorageClass: aws.String("STANDARD"),
+			ETag:         aws.String("\"etag\""),
+			Owner:        &s3.Owner{DisplayName: aws.String("owner"), ID: aws.String("id")},
+		})
+
+		expected := Object{
+			Key:          "key",
+			Size:         10,
+			LastModified: now,
+			StorageClass: "STANDARD",
+			ETag:         "\"etag\"",
+			Owner:        "owner",
+		}
+		if o != expected {
+			t.Fatalf("Unexpected Object: {Expected: %v, Actual: %v}", expected, o)
+		}
+	}
+
+	// Owner without display name
+	{
+		o := newObject(&s3.Object{
+			Key:   aws.String("key"),
+			Owner: &s3.Owner{ID: aws.String("id")},
+		})
+
+		if o.Owner != "id" {
+			t.Fatalf("Expected owner ID to be used without a display name: %v", o.Owner)
+		}
+	}
+
+	// Missing fields
+	{
+		o := newObject(&s3.Object{})
+		if o != (Object{}) {
+			t.Fatalf("Expected empty Object for empty input: %v", o)
+		}
+	}
+}
+
+func Test_newObjectInfo(t *testing.T) {
+	// All fields
+	{
+		now := time.Now()
+		info := newObjectInfo("key", &s3.HeadObjectOutput{
+			ContentLength:        aws.Int64(10),
+			ContentType:          aws.String("text/plain"),
+			ETag:                 aws.String("\"etag\""),
+			LastModified:         &now,
+			StorageClass:         aws.String("GLACIER"),
+			ServerSideEncryption: aws.String("aws:kms"),
+			SSEKMSKeyId:          aws.String("key-id"),
+			VersionId:            aws.String("version"),
+			Restore:              aws.String("ongoing-request=\"true\""),
+			Metadata:             map[string]*string{"Author": aws.String("kyle")},
+		})
+
+		if info.Key != "key" || info.Size != 10 || info.ContentType != "text/plain" || info.ETag != "\"etag\"" || !info.LastModified.Equal(now) {
+			t.Fatalf("Unexpected object info: %v", info)
+		} else if info.StorageClass != "GLACIER" || info.ServerSideEncryption != "aws:kms" || info.KMSKeyID != "key-id" {
+			t.Fatalf("Unexpected object info: %v", info)
+		} else if info.VersionID != "version" || info.Restore != "ongoing-request=\"true\"" {
+			t.Fatalf("Unexpected object info: %v", info)
+		} else if len(info.Metadata) != 1 || info.Metadata["author"] != "kyle" {
+			t.Fatalf("Unexpected metadata: %v", info.Metadata)
+		}
+	}
+
+	// Missing fields
+	{
+		info := newObjectInfo("key", &s3.HeadObjectOutput{})
+		if info.Key != "key" || info.Size != 0 || len(info.ETag) != 0 || len(info.Metadata) != 0 {
+			t.Fatalf("Unexpected object info: %v", info)
+		} else if info.StorageClass != s3.StorageClassStandard {
+			t.Fatalf("Expected standard storage class when omitted: %v", info.StorageClass)
+		}
+	}
+}
+
+func TestDeleteError_Error(t *testing.T) {
+	e := DeleteError{Key: "key", Code: "AccessDenied", Message: "Access Denied"}
+
+	if msg := e.Error(); msg != "key: Access Denied (AccessDenied)" {
+		t.Fatalf("Unexpected error message: %v", msg)
+	}
+}
+
+func Test_newDeleteError(t *testing.T) {
+	e := newDeleteError(&s3.Error{Key: aws.String("key"), Code: aws.String("code"), Message: aws.String("message")})
+
+	if e != (DeleteError{Key: "key", Code: "code", Message: "message"}) {
+		t.Fatalf("Unexpected DeleteError: %v", e)
+	}
+}
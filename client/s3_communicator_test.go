@@ -9,14 +9,33 @@ import (
 // Mock s3Communicator
 
 type mockS3Communicator struct {
-	listBucketsCallback func(i *s3.ListBucketsInput) (*s3.ListBucketsOutput, error)
-	listObjectsCallback func(i *s3.ListObjectsInput) (*s3.ListObjectsOutput, error)
+	listBucketsCallback        func(i *s3.ListBucketsInput) (*s3.ListBucketsOutput, error)
+	listObjectsCallback        func(i *s3.ListObjectsInput) (*s3.ListObjectsOutput, error)
+	listObjectsV2PagesCallback func(i *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error
+
+	listObjectVersionsPagesCallback func(i *s3.ListObjectVersionsInput, fn func(*s3.ListObjectVersionsOutput, bool) bool) error
+
+	createBucketCallback func(i *s3.CreateBucketInput) (*s3.CreateBucketOutput, error)
+	deleteBucketCallback func(i *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error)
+
+	getBucketLocationCallback   func(i *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error)
+	getBucketVersioningCallback func(i *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error)
+	getBucketEncryptionCallback func(i *getBucketEncryptionInput) (*getBucketEncryptionOutput, error)
 
 	headBucketCallback func(i *s3.HeadBucketInput) (*s3.HeadBucketOutput, error)
 	headObjectCallback func(i *s3.HeadObjectInput) (*s3.HeadObjectOutput, error)
 
-	getObjectCallback func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error)
-	putObjectCallback func(i *s3.PutObjectInput) (*s3.PutObjectOutput, error)
+	getObjectCallback     func(i *s3.GetObjectInput) (*s3.GetObjectOutput, error)
+	putObjectCallback     func(i *s3.PutObjectInput) (*s3.PutObjectOutput, error)
+	deleteObjectCallback  func(i *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error)
+	deleteObjectsCallback func(i *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error)
+	copyObjectCallback    func(i *s3.CopyObjectInput) (*s3.CopyObjectOutput, error)
+
+	createMultipartUploadCallback   func(i *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error)
+	uploadPartCallback              func(i *s3.UploadPartInput) (*s3.UploadPartOutput, error)
+	uploadPartCopyCallback          func(i *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error)
+	completeMultipartUploadCallback func(i *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error)
+	abortMultipartUploadCallback    func(i *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error)
 }
 
 func (m *mockS3Communicator) ListBuckets(i *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
@@ -27,6 +46,34 @@ func (m *mockS3Communicator) ListObjects(i *s3.ListObjectsInput) (*s3.ListObject
 	return m.listObjectsCallback(i)
 }
 
+func (m *mockS3Communicator) ListObjectsV2Pages(i *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+	return m.listObjectsV2PagesCallback(i, fn)
+}
+
+func (m *mockS3Communicator) ListObjectVersionsPages(i *s3.ListObjectVersionsInput, fn func(*s3.ListObjectVersionsOutput, bool) bool) error {
+	return m.listObjectVersionsPagesCallback(i, fn)
+}
+
+func (m *mockS3Communicator) CreateBucket(i *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+	return m.createBucketCallback(i)
+}
+
+func (m *mockS3Communicator) DeleteBucket(i *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error) {
+	return m.deleteBucketCallback(i)
+}
+
+func (m *mockS3Communicator) GetBucketLocation(i *s3.GetBucketLocationInput) (*s3.GetBucketLocationOutput, error) {
+	return m.getBucketLocationCallback(i)
+}
+
+func (m *mockS3Communicator) GetBucketVersioning(i *s3.GetBucketVersioningInput) (*s3.GetBucketVersioningOutput, error) {
+	return m.getBucketVersioningCallback(i)
+}
+
+func (m *mockS3Communicator) GetBucketEncryption(i *getBucketEncryptionInput) (*getBucketEncryptionOutput, error) {
+	return m.getBucketEncryptionCallback(i)
+}
+
 func (m *mockS3Communicator) HeadBucket(i *s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
 	return m.headBucketCallback(i)
 }
@@ -43,14 +90,51 @@ func (m *mockS3Communicator) PutObject(i *s3.PutObjectInput) (*s3.PutObjectOutpu
 	return m.putObjectCallback(i)
 }
 
+func (m *mockS3Communicator) DeleteObject(i *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	return m.deleteObjectCallback(i)
+}
+
+func (m *mockS3Communicator) DeleteObjects(i *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+	return m.deleteObjectsCallback(i)
+}
+
+func (m *mockS3Communicator) CopyObject(i *s3.CopyObjectInput) (*s3.CopyObjectOutput, error) {
+	return m.copyObjectCallback(i)
+}
+
+func (m *mockS3Communicator) CreateMultipartUpload(i *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+	return m.createMultipartUploadCallback(i)
+}
+
+func (m *mockS3Communicator) UploadPart(i *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+	return m.uploadPartCallback(i)
+}
+
+func (m *mockS3Communicator) UploadPartCopy(i *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error) {
+	return m.uploadPartCopyCallback(i)
+}
+
+func (m *mockS3Communicator) CompleteMultipartUpload(i *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+	return m.completeMultipartUploadCallback(i)
+}
+
+func (m *mockS3Communicator) AbortMultipartUpload(i *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error) {
+	return m.abortMultipartUploadCallback(i)
+}
+
 // Mock ReadCloser
 
 type mockReadCloser struct {
 	index int
 	data  []byte
+	err   error
 }
 
 func (m *mockReadCloser) Read(b []byte) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
 	var i int
 	for i = 0; i < len(b); i++ {
 		if i+m.index >= len(m.data) {
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// opGetBucketEncryption is the name of the GetBucketEncryption operation.
+	opGetBucketEncryption = "GetBucketEncryption"
+
+	// errCodeNoBucketEncryption is the error code returned by GetBucketEncryption when a bucket has no default
+	// encryption configured.
+	errCodeNoBucketEncryption = "ServerSideEncryptionConfigurationNotFoundError"
+)
+
+// s3Service extends the Amazon S3 API client with operations that are not provided by the vendored SDK.
+type s3Service struct {
+	*s3.S3
+}
+
+// getBucketEncryptionInput is the input of the GetBucketEncryption operation.
+type getBucketEncryptionInput struct {
+	_ struct{} `type:"structure"`
+
+	Bucket *string `location:"uri" locationName:"Bucket" type:"string" required:"true"`
+}
+
+// getBucketEncryptionOutput is the output of the GetBucketEncryption operation.
+type getBucketEncryptionOutput struct {
+	_ struct{} `type:"structure" payload:"ServerSideEncryptionConfiguration"`
+
+	ServerSideEncryptionConfiguration *serverSideEncryptionConfiguration `type:"structure"`
+}
+
+// serverSideEncryptionConfiguration is the default encryption configuration of a bucket.
+type serverSideEncryptionConfiguration struct {
+	_ struct{} `type:"structure"`
+
+	Rules []*serverSideEncryptionRule `locationName:"Rule" type:"list" flattened:"true"`
+}
+
+// serverSideEncryptionRule is a single rule of a bucket's default encryption configuration.
+type serverSideEncryptionRule struct {
+	_ struct{} `type:"structure"`
+
+	ApplyServerSideEncryptionByDefault *serverSideEncryptionByDefault `type:"structure"`
+}
+
+// serverSideEncryptionByDefault describes the encryption applied to new objects in a bucket.
+type serverSideEncryptionByDefault struct {
+	_ struct{} `type:"structure"`
+
+	SSEAlgorithm   *string `type:"string"`
+	KMSMasterKeyID *string `type:"string"`
+}
+
+// GetBucketEncryption returns the default encryption configuration of a bucket.
+//
+// The request is built on the underlying client, so that it is signed, sent and unmarshaled in the same way as
+// the operations provided by the SDK.
+func (s s3Service) GetBucketEncryption(input *getBucketEncryptionInput) (*getBucketEncryptionOutput, error) {
+	op := &request.Operation{
+		Name:       opGetBucketEncryption,
+		HTTPMethod: "GET",
+		HTTPPath:   "/{Bucket}?encryption",
+	}
+
+	output := &getBucketEncryptionOutput{}
+	req := s.NewRequest(op, input, output)
+	return output, req.Send()
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// newTestService returns an s3Service that sends requests to the handler provided.
+func newTestService(h http.HandlerFunc) (s3Service, *httptest.Server) {
+	server := httptest.NewServer(h)
+
+	return s3Service{s3.New(session.New(), &aws.Config{
+		Region:           aws.String("us-east-1"),
+		Endpoint:         aws.String(server.URL),
+		S3ForcePathStyle: aws.Bool(true),
+		DisableSSL:       aws.Bool(true),
+		Credentials:      credentials.NewStaticCredentials("id", "secret", ""),
+	})}, server
+}
+
+func TestS3Service_GetBucketEncryption(t *testing.T) {
+	// Positive case
+	{
+		svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := r.URL.Query()["encryption"]; r.Method != "GET" || r.URL.Path != "/bucket" || !ok {
+				t.Errorf("Unexpected request: %v %v", r.Method, r.URL)
+			}
+
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ServerSideEncryptionConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+	<Rule>
+		<ApplyServerSideEncryptionByDefault>
+			<SSEAlgorithm>aws:kms</SSEAlgorithm>
+			<KMSMasterKeyID>key-id</KMSMasterKeyID>
+		</ApplyServerSideEncryptionByDefault>
+	</Rule>
+</ServerSideEncryptionConfiguration>`))
+		})
+		defer server.Close()
+
+		out, err := svc.GetBucketEncryption(&getBucketEncryptionInput{Bucket: aws.String("bucket")})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rules := out.ServerSideEncryptionConfiguration.Rules
+		if len(rules) != 1 {
+			t.Fatalf("Unexpected rules: %v", rules)
+		} else if d := rules[0].ApplyServerSideEncryptionByDefault; *d.SSEAlgorithm != "aws:kms" || *d.KMSMasterKeyID != "key-id" {
+			t.Fatalf("Unexpected default encryption: %v", d)
+		}
+	}
+
+	// No encryption configured
+	{
+		svc, server := newTestService(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>` + errCodeNoBucketEncryption + `</Code><Message>The server side encryption configuration was not found</Message></Error>`))
+		})
+		defer server.Close()
+
+		_, err := svc.GetBucketEncryption(&getBucketEncryptionInput{Bucket: aws.String("bucket")})
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != errCodeNoBucketEncryption {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// MinPartSize is the smallest part of a multipart upload allowed by Amazon S3, other than the last part.
+	MinPartSize = 5 * 1024 * 1024
+
+	// MaxPartSize is the largest part of a multipart upload allowed by Amazon S3.
+	MaxPartSize = 5 * 1024 * 1024 * 1024
+
+	// maxUploadParts is the largest number of parts allowed in a multipart upload by Amazon S3.
+	maxUploadParts = 10000
+
+	// DefaultPartSize is the size of each part of a multipart upload, or range of a download, unless otherwise
+	// provided.
+	DefaultPartSize = 16 * 1024 * 1024
+
+	// DefaultUploadConcurrency is the number of parts of a multipart upload uploaded at once, unless otherwise
+	// provided.
+	DefaultUploadConcurrency = 4
+
+	// errCodeNoSuchUpload is the error code returned when a multipart upload has been completed, aborted or has
+	// expired.
+	errCodeNoSuchUpload = "NoSuchUpload"
+)
+
+// ErrUploadInterrupted is returned when an upload is cancelled before it completes.
+var ErrUploadInterrupted = errors.New("Upload interrupted")
+
+// UploadOptions configures how files are uploaded, where the zero value uses the default part size and
+// concurrency.
+type UploadOptions struct {
+	// PartSize is the size of each part of a multipart upload. Files larger than a single part are uploaded using
+	// a multipart upload.
+	PartSize int64
+
+	// Concurrency is the number of parts of a multipart upload uploaded at once.
+	Concurrency int
+
+	// Cancel interrupts the upload when closed. Any multipart upload in progress is kept in the journal to be
+	// resumed, or aborted if the client has no journal.
+	Cancel <-chan struct{}
+}
+
+// PutObject uploads a file to exactly the key provided in an Amazon S3 bucket, overwriting any existing object.
+//
+// Files larger than the part size are uploaded in parts, concurrently, using a multipart upload.
+func (c Client) PutObject(bucket, key string, file *os.File, opts UploadOptions) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if size := info.Size(); size > opts.partSize(size) {
+		return c.multipartUpload(bucket, key, file, info, opts)
+	}
+
+	select {
+	case <-opts.Cancel:
+		return ErrUploadInterrupted
+	default:
+	}
+
+	input := s3.PutObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+		Body:   file,
+	}
+	_, err = c.s3.PutObject(&input)
+	return err
+}
+
+// multipartUpload uploads a file in parts, with up to the configured concurrency of parts uploaded at once.
+//
+// If any part fails to upload, or the upload is cancelled, no further parts are uploaded. The multipart upload and
+// the parts completed so far are recorded in the journal, so that uploading the same, unchanged file to the same
+// key resumes the upload with only the remaining parts. Without a journal, the multipart upload is aborted instead
+// so that no partial object, or orphaned parts, remain.
+func (c Client) multipartUpload(bucket, key string, file *os.File, info os.FileInfo, opts UploadOptions) error {
+	t, err := c.startUpload(bucket, key, file, info, opts)
+	if err != nil {
+		return err
+	}
+
+	size := info.Size()
+	parts := make([]*s3.CompletedPart, (size+t.PartSize-1)/t.PartSize)
+	for _, p := range t.Parts {
+		if p.Number >= 1 && p.Number <= int64(len(parts)) {
+			parts[p.Number-1] = &s3.CompletedPart{
+				ETag:       aws.String(p.ETag),
+				PartNumber: aws.Int64(p.Number),
+			}
+		}
+	}
+
+	// stop is closed by the first failure, or cancellation, to prevent any further parts from being uploaded.
+	stop := make(chan struct{})
+	var once sync.Once
+	var uploadErr error
+	fail := func(err error) {
+		once.Do(func() {
+			uploadErr = err
+			close(stop)
+		})
+	}
+
+	// record adds a completed part to the journal, so that it isn't uploaded again if the upload is resumed.
+	var mu sync.Mutex
+	record := func(part *s3.CompletedPart) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		t.Parts = append(t.Parts, TransferPart{
+			Number: aws.Int64Value(part.PartNumber),
+			ETag:   aws.StringValue(part.ETag),
+		})
+		return c.journal.save(t)
+	}
+
+	nums := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.concurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for num := range nums {
+				select {
+				case <-stop:
+					continue
+				case <-opts.Cancel:
+					fail(ErrUploadInterrupted)
+					continue
+				default:
+				}
+
+				offset := int64(num) * t.PartSize
+				length := t.PartSize
+				if offset+length > size {
+					length = size - offset
+				}
+
+				resp, err := c.s3.UploadPart(&s3.UploadPartInput{
+					Bucket:        &bucket,
+					Key:           &key,
+					UploadId:      &t.UploadID,
+					PartNumber:    aws.Int64(int64(num + 1)),
+					Body:          io.NewSectionReader(file, offset, length),
+					ContentLength: aws.Int64(length),
+				})
+				if err != nil {
+					fail(err)
+					continue
+				}
+
+				parts[num] = &s3.CompletedPart{
+					ETag:       resp.ETag,
+					PartNumber: aws.Int64(int64(num + 1)),
+				}
+				if c.journal != nil {
+					if err := record(parts[num]); err != nil {
+						fail(err)
+					}
+				}
+			}
+		}()
+	}
+
+queue:
+	for num := range parts {
+		if parts[num] != nil {
+			continue
+		}
+
+		select {
+		case nums <- num:
+		case <-stop:
+			break queue
+		}
+	}
+	close(nums)
+	wg.Wait()
+
+	if uploadErr != nil {
+		return c.stopUpload(t, uploadErr)
+	}
+
+	_, err = c.s3.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          &bucket,
+		Key:             &key,
+		UploadId:        &t.UploadID,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return c.stopUpload(t, err)
+	}
+
+	if c.journal != nil {
+		return c.journal.remove(t)
+	}
+
+	return nil
+}
+
+// startUpload returns the multipart upload of a file, resuming the upload recorded in the journal if the file is
+// unchanged since it was started, or creating a new multipart upload otherwise.
+func (c Client) startUpload(bucket, key string, file *os.File, info os.FileInfo, opts UploadOptions) (Transfer, error) {
+	path, err := filepath.Abs(file.Name())
+	if err != nil {
+		return Transfer{}, err
+	}
+
+	if c.journal != nil {
+		t, ok, err := c.journal.find(TransferUpload, bucket, key, path)
+		if err != nil {
+			return t, err
+		} else if ok && t.Size == info.Size() && t.ModTime.Equal(info.ModTime()) && t.PartSize > 0 {
+			return t, nil
+		} else if ok {
+			// The file has changed, so the parts already uploaded can't be used.
+			c.abortMultipartUpload(bucket, key, &t.UploadID)
+			if err := c.journal.remove(t); err != nil {
+				return t, err
+			}
+		}
+	}
+
+	upload, err := c.s3.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return Transfer{}, err
+	}
+
+	t := Transfer{
+		Kind:     TransferUpload,
+		Bucket:   bucket,
+		Key:      key,
+		Path:     path,
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+		Started:  time.Now(),
+		UploadID: aws.StringValue(upload.UploadId),
+		PartSize: opts.partSize(info.Size()),
+	}
+	if c.journal != nil {
+		if err := c.journal.save(t); err != nil {
+			c.abortMultipartUpload(bucket, key, upload.UploadId)
+			return t, err
+		}
+	}
+
+	return t, nil
+}
+
+// stopUpload handles a multipart upload that failed with err, which is returned.
+//
+// The upload is kept in the journal to be resumed, unless Amazon S3 no longer has the upload. Without a journal,
+// the upload is aborted.
+func (c Client) stopUpload(t Transfer, err error) error {
+	if c.journal == nil {
+		c.abortMultipartUpload(t.Bucket, t.Key, &t.UploadID)
+	} else if aerr, ok := err.(awserr.Error); ok && aerr.Code() == errCodeNoSuchUpload {
+		c.journal.remove(t)
+	}
+
+	return err
+}
+
+// partSize returns the size of each part when uploading a file of the size provided, within the limits of
+// Amazon S3. The part size is increased as necessary to keep the number of parts within maxUploadParts.
+func (o UploadOptions) partSize(size int64) int64 {
+	partSize := clampPartSize(o.PartSize)
+	if min := (size + maxUploadParts - 1) / maxUploadParts; partSize < min {
+		partSize = min
+	}
+
+	return partSize
+}
+
+// clampPartSize returns the part size provided within the limits of Amazon S3, or the default part size if it
+// isn't provided.
+func clampPartSize(partSize int64) int64 {
+	switch {
+	case partSize <= 0:
+		return DefaultPartSize
+	case partSize < MinPartSize:
+		return MinPartSize
+	case partSize > MaxPartSize:
+		return MaxPartSize
+	}
+
+	return partSize
+}
+
+// concurrency returns the number of parts to upload at once.
+func (o UploadOptions) concurrency() int {
+	if o.Concurrency <= 0 {
+		return DefaultUploadConcurrency
+	}
+
+	return o.Concurrency
+}
@@ -0,0 +1,325 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func TestClient_PutObject(t *testing.T) {
+	file, _ := ioutil.TempFile("", "")
+	defer os.Remove(file.Name())
+	bucket := "bucket"
+	key := "folder"
+
+	// Positive case, the key is never treated as a directory
+	{
+		var mockS3 mockS3Communicator
+		mockS3.putObjectCallback = func(i *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			if *i.Bucket != bucket || *i.Key != key || i.Body != file {
+				t.Fatalf("Unexpected PutObjectInput: %v", i)
+			}
+
+			return nil, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.PutObject(bucket, key, file, UploadOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Cancelled
+	{
+		cancel := make(chan struct{})
+		close(cancel)
+
+		var mockS3 mockS3Communicator
+		mockS3.putObjectCallback = func(i *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			t.Fatal("PutObject should not be called once cancelled")
+			return nil, nil
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.PutObject(bucket, key, file, UploadOptions{Cancel: cancel}); err != ErrUploadInterrupted {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+
+	// Negative case
+	{
+		mockErr := errors.New("Mock error")
+
+		var mockS3 mockS3Communicator
+		mockS3.putObjectCallback = func(i *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			return nil, mockErr
+		}
+
+		c := Client{s3: &mockS3}
+		if err := c.PutObject(bucket, key, file, UploadOptions{}); err != mockErr {
+			t.Fatalf("Expected mock error to be returned: %v", err)
+		}
+	}
+}
+
+func TestClient_PutObject_multipart(t *testing.T) {
+	// Create a file of two full parts and a partial part, where each part is filled with its part number.
+	file, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.Write(bytes.Repeat([]byte{1}, MinPartSize))
+	file.Write(bytes.Repeat([]byte{2}, MinPartSize))
+	file.Write(bytes.Repeat([]byte{3}, 100))
+
+	// mockS3 returns a communicator that validates and records each part uploaded, failing the part numbers
+	// provided.
+	mockS3 := func(parts *[]int64, fails ...int64) *mockS3Communicator {
+		var mu sync.Mutex
+		var m mockS3Communicator
+		m.createMultipartUploadCallback = func(i *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+			if *i.Bucket != "bucket" || *i.Key != "key" {
+				t.Fatalf("Unexpected CreateMultipartUploadInput: %v", i)
+			}
+			return &s3.CreateMultipartUploadOutput{UploadId: aws.String("id")}, nil
+		}
+		m.uploadPartCallback = func(i *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+			for _, f := range fails {
+				if *i.PartNumber == f {
+					return nil, errors.New("Mock Error")
+				}
+			}
+
+			b, err := ioutil.ReadAll(i.Body)
+			if err != nil {
+				t.Fatal(err)
+			} else if *i.UploadId != "id" || int64(len(b)) != *i.ContentLength {
+				t.Fatalf("Unexpected UploadPartInput: %v", i)
+			} else if !bytes.Equal(b, bytes.Repeat([]byte{byte(*i.PartNumber)}, len(b))) {
+				t.Fatalf("Unexpected contents of part %v", *i.PartNumber)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			*parts = append(*parts, *i.PartNumber)
+			return &s3.UploadPartOutput{ETag: aws.String(string('a' + byte(*i.PartNumber)))}, nil
+		}
+		m.completeMultipartUploadCallback = func(i *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+			for n, p := range i.MultipartUpload.Parts {
+				if *p.PartNumber != int64(n+1) || *p.ETag != string('a'+byte(n+1)) {
+					t.Fatalf("Unexpected part completed: %v", p)
+				}
+			}
+			return nil, nil
+		}
+		m.abortMultipartUploadCallback = func(i *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error) {
+			t.Fatalf("Unexpected abort: %v", i)
+			return nil, nil
+		}
+		m.putObjectCallback = func(i *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			t.Fatal("PutObject should not be used for files larger than a part")
+			return nil, nil
+		}
+		return &m
+	}
+
+	// Positive case
+	{
+		var parts []int64
+		c := Client{s3: mockS3(&parts)}
+		if err := c.PutObject("bucket", "key", file, UploadOptions{PartSize: MinPartSize, Concurrency: 2}); err != nil {
+			t.Fatal(err)
+		}
+
+		sort.Sort(int64s(parts))
+		if len(parts) != 3 || parts[0] != 1 || parts[2] != 3 {
+			t.Fatalf("Unexpected parts uploaded: %v", parts)
+		}
+	}
+
+	// A file within a single part is uploaded with one request
+	{
+		var parts []int64
+		var put bool
+		m := mockS3(&parts)
+		m.putObjectCallback = func(i *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			put = true
+			return nil, nil
+		}
+
+		c := Client{s3: m}
+		if err := c.PutObject("bucket", "key", file, UploadOptions{}); err != nil {
+			t.Fatal(err)
+		} else if !put || len(parts) > 0 {
+			t.Fatalf("Expected a single PutObject request: %v", parts)
+		}
+	}
+
+	// Part error, and cancellation, abort the upload
+	for _, cancelled := range []bool{false, true} {
+		var parts []int64
+		var aborted bool
+		m := mockS3(&parts, 2)
+		m.completeMultipartUploadCallback = func(i *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+			t.Fatal("Upload should not be completed after an error")
+			return nil, nil
+		}
+		m.abortMultipartUploadCallback = func(i *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error) {
+			if *i.UploadId != "id" {
+				t.Fatalf("Unexpected AbortMultipartUploadInput: %v", i)
+			}
+			aborted = true
+			return nil, nil
+		}
+
+		// Cancel once the first part has been uploaded.
+		opts := UploadOptions{PartSize: MinPartSize, Concurrency: 1}
+		if cancelled {
+			cancel := make(chan struct{})
+			opts.Cancel = cancel
+			upload := m.uploadPartCallback
+			m.uploadPartCallback = func(i *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+				defer close(cancel)
+				return upload(i)
+			}
+		}
+
+		c := Client{s3: m}
+		err := c.PutObject("bucket", "key", file, opts)
+		if cancelled && err != ErrUploadInterrupted {
+			t.Fatalf("Unexpected error returned: %v", err)
+		} else if err == nil {
+			t.Fatal("Expected error to be returned")
+		} else if !aborted {
+			t.Fatal("Expected multipart upload to be aborted")
+		} else if len(parts) != 1 {
+			t.Fatalf("Expected no further parts to be uploaded: %v", parts)
+		}
+	}
+
+	// With a journal, a failed upload is kept and then resumed with only the remaining parts
+	{
+		dir, err := ioutil.TempDir("", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		j := NewJournal(dir)
+
+		var parts []int64
+		m := mockS3(&parts, 2)
+		m.completeMultipartUploadCallback = func(i *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+			t.Fatal("Upload should not be completed after an error")
+			return nil, nil
+		}
+
+		c := Client{s3: m, journal: j}
+		if err := c.PutObject("bucket", "key", file, UploadOptions{PartSize: MinPartSize, Concurrency: 1}); err == nil {
+			t.Fatal("Expected error to be returned")
+		}
+
+		transfers, err := j.Transfers()
+		if err != nil {
+			t.Fatal(err)
+		} else if len(transfers) != 1 || transfers[0].UploadID != "id" || transfers[0].PartSize != MinPartSize ||
+			len(transfers[0].Parts) != 1 || transfers[0].Parts[0].Number != 1 {
+			t.Fatalf("Unexpected transfers recorded: %v", transfers)
+		}
+
+		// Resume with the same options, where only the concurrency may differ.
+		parts = nil
+		m = mockS3(&parts)
+		m.createMultipartUploadCallback = func(i *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+			t.Fatal("A new multipart upload should not be created when resuming")
+			return nil, nil
+		}
+
+		c = Client{s3: m, journal: j}
+		if err := c.PutObject("bucket", "key", file, UploadOptions{PartSize: MinPartSize, Concurrency: 2}); err != nil {
+			t.Fatal(err)
+		}
+
+		sort.Sort(int64s(parts))
+		if len(parts) != 2 || parts[0] != 2 || parts[1] != 3 {
+			t.Fatalf("Unexpected parts uploaded: %v", parts)
+		} else if transfers, err := j.Transfers(); err != nil || len(transfers) != 0 {
+			t.Fatalf("Expected the completed upload to be removed from the journal: %v, %v", transfers, err)
+		}
+	}
+
+	// With a journal, an upload of a file that has since changed is aborted and started again
+	{
+		dir, err := ioutil.TempDir("", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		j := NewJournal(dir)
+		j.save(Transfer{
+			Kind:     TransferUpload,
+			Bucket:   "bucket",
+			Key:      "key",
+			Path:     file.Name(),
+			Size:     MinPartSize * 3,
+			UploadID: "old",
+			PartSize: MinPartSize,
+			Parts:    []TransferPart{{Number: 1, ETag: "old"}},
+		})
+
+		var parts []int64
+		var aborted bool
+		m := mockS3(&parts)
+		m.abortMultipartUploadCallback = func(i *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error) {
+			if *i.UploadId != "old" {
+				t.Fatalf("Unexpected AbortMultipartUploadInput: %v", i)
+			}
+			aborted = true
+			return nil, nil
+		}
+
+		c := Client{s3: m, journal: j}
+		if err := c.PutObject("bucket", "key", file, UploadOptions{PartSize: MinPartSize}); err != nil {
+			t.Fatal(err)
+		} else if !aborted || len(parts) != 3 {
+			t.Fatalf("Expected the previous upload to be aborted and every part uploaded: %v, %v", aborted, parts)
+		}
+	}
+}
+
+func TestUploadOptions_partSize(t *testing.T) {
+	tests := []struct {
+		partSize int64
+		size     int64
+		expected int64
+	}{
+		{0, 0, DefaultPartSize},
+		{0, DefaultPartSize * 100, DefaultPartSize},
+		{1, 100, MinPartSize},
+		{MinPartSize * 2, 100, MinPartSize * 2},
+		{MaxPartSize * 2, 100, MaxPartSize},
+		{MinPartSize, MinPartSize * maxUploadParts, MinPartSize},
+		{MinPartSize, MinPartSize*maxUploadParts + 1, MinPartSize + 1},
+	}
+
+	for _, test := range tests {
+		opts := UploadOptions{PartSize: test.partSize}
+		if actual := opts.partSize(test.size); actual != test.expected {
+			t.Fatalf("Unexpected part size for %v: {Expected: %v, Actual: %v}", test, test.expected, actual)
+		}
+	}
+}
+
+// int64s sorts a slice of int64s in increasing order.
+type int64s []int64
+
+func (s int64s) Len() int           { return len(s) }
+func (s int64s) Less(i, j int) bool { return s[i] < s[j] }
+func (s int64s) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
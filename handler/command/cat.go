@@ -0,0 +1,135 @@
+package command
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// catFlagGunzip indicates that gzip compressed objects should be decompressed as they are printed.
+	catFlagGunzip = "z"
+
+	// catSniffSize is the number of bytes at the start of an object that are inspected to determine if it is binary.
+	catSniffSize = 8000
+
+	// gzipExtension is the extension of objects that are decompressed by the gunzip flag.
+	gzipExtension = ".gz"
+)
+
+// CatCommand prints the contents of objects.
+type CatCommand struct {
+	s3  S3Client
+	con *context.Context
+	in  Inputter
+
+	args []string
+}
+
+// Execute performs a 'cat' command by streaming the contents of each target object directly to the output,
+// without writing them to disk.
+//
+// Objects that appear to be binary are only printed after prompting the user for confirmation.
+func (cat CatCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(cat.args)
+	if len(flags.Args) == 0 {
+		return errors.New("Missing target file.")
+	}
+
+	// Validate every target before printing any of them.
+	targets := make([][]string, len(flags.Args))
+	for i, arg := range flags.Args {
+		p, err := resolveFile(cat.s3, cat.con, arg)
+		if err != nil {
+			return err
+		}
+		targets[i] = p
+	}
+
+	for _, p := range targets {
+		if err := cat.print(out, p, flags.Has(catFlagGunzip)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// print streams the contents of a single object to the output, decompressing it if requested and the object is
+// gzip compressed.
+func (cat CatCommand) print(out Outputter, p []string, gunzip bool) error {
+	body, err := cat.s3.OpenObject(splitKeyPath(p))
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	var r io.Reader = body
+	if gunzip && strings.HasSuffix(p[len(p)-1], gzipExtension) {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	// Inspect the start of the contents before anything is printed.
+	br := bufio.NewReaderSize(r, catSniffSize)
+	start, err := br.Peek(catSniffSize)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	if isBinary(start) && !confirm(out, cat.in, displayPath(p)+" appears to be a binary file, print it anyway?") {
+		out.Write("\nSkipped: " + displayPath(p))
+		return nil
+	}
+
+	out.Write("\n")
+	_, err = io.Copy(outputWriter{out}, br)
+	return err
+}
+
+// IsLongRunning returns false, as the contents are output as they are received and the loading indicator would
+// otherwise interfere with both the contents and the binary confirmation prompt.
+func (CatCommand) IsLongRunning() bool {
+	return false
+}
+
+// NewCat initializes and returns a CatCommand.
+func NewCat(s3 S3Client, con *context.Context, in Inputter, args []string) CatCommand {
+	return CatCommand{
+		s3:   s3,
+		con:  con,
+		in:   in,
+		args: args,
+	}
+}
+
+// isBinary returns a bool indicating if the start of a file appears to be binary, rather than text, by checking
+// for NUL bytes or invalid UTF-8.
+//
+// A multi-byte character that is cut off at the end of the sample is not considered invalid.
+func isBinary(b []byte) bool {
+	for len(b) > 0 {
+		if b[0] == 0 {
+			return true
+		}
+
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size == 1 {
+			return utf8.FullRune(b)
+		}
+
+		b = b[size:]
+	}
+
+	return false
+}
@@ -0,0 +1,185 @@
+package command
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+// mockOpenObject returns an OpenObject callback that provides the contents of each key in a bucket.
+func mockOpenObject(contents map[string]string) func(string, string) (io.ReadCloser, error) {
+	return func(bucket, key string) (io.ReadCloser, error) {
+		c, ok := contents[bucket+"/"+key]
+		if !ok {
+			return nil, errors.New("No such key: " + key)
+		}
+
+		return ioutil.NopCloser(strings.NewReader(c)), nil
+	}
+}
+
+// gzipString returns the gzip compressed form of a string.
+func gzipString(s string) string {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	w.Write([]byte(s))
+	w.Close()
+	return b.String()
+}
+
+func TestCatCommand_Execute(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "a.txt"}, {Key: "b.txt"}, {Key: "c.txt.gz"}, {Key: "image.png"}},
+	}
+	contents := map[string]string{
+		"bucket/a.txt":     "Hello\nWorld\n",
+		"bucket/b.txt":     "Héllo",
+		"bucket/c.txt.gz":  gzipString("Compressed"),
+		"bucket/image.png": "\x89PNG\r\n\x1a\n\x00\x00",
+	}
+
+	tests := []struct {
+		args     []string
+		input    []string
+		expected string
+	}{
+		{[]string{"a.txt"}, nil, "\nHello\nWorld\n"},
+		{[]string{"a.txt", "/bucket/b.txt"}, nil, "\nHello\nWorld\n\nHéllo"},
+		{[]string{"-z", "c.txt.gz"}, nil, "\nCompressed"},
+		{[]string{"-z", "a.txt"}, nil, "\nHello\nWorld\n"},
+		{[]string{"image.png"}, []string{"n"}, "\n/bucket/image.png appears to be a binary file, print it anyway?" + confirmSuffix + "\nSkipped: /bucket/image.png"},
+		{[]string{"image.png"}, []string{"y"}, "\n/bucket/image.png appears to be a binary file, print it anyway?" + confirmSuffix + "\n" + contents["bucket/image.png"]},
+	}
+
+	for _, test := range tests {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		s3.openObjectCallback = mockOpenObject(contents)
+
+		cat := NewCat(&s3, &con, &mockInputter{lines: test.input}, test.args)
+		if err := cat.Execute(&out); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.args, err)
+		}
+
+		if output := strings.Join(out.output, ""); output != test.expected {
+			t.Fatalf("Unexpected output for %v: {Expected: %q, Actual: %q}", test.args, test.expected, output)
+		}
+	}
+}
+
+func TestCatCommand_Execute_invalid(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "a.txt"}, {Key: "folder/b.txt"}, {Key: "bad.gz"}},
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"/"},
+		{"/bucket"},
+		{"folder"},
+		{"fake.txt"},
+		{"a.txt", "fake.txt"},
+	} {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.openObjectCallback = func(bucket, key string) (io.ReadCloser, error) {
+			t.Fatalf("OpenObject should not be called for invalid args: %v", args)
+			return nil, nil
+		}
+
+		cat := NewCat(&s3, &con, nil, args)
+		if err := cat.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+
+	// Invalid gzip contents
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		s3.openObjectCallback = mockOpenObject(map[string]string{"bucket/bad.gz": "Not compressed"})
+
+		cat := NewCat(&s3, &con, nil, []string{"-z", "bad.gz"})
+		if err := cat.Execute(&out); err == nil {
+			t.Fatal("Expected error for invalid gzip contents")
+		}
+	}
+
+	// S3 error
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		mockErr := errors.New("Mock Error")
+
+		s3.openObjectCallback = func(bucket, key string) (io.ReadCloser, error) {
+			return nil, mockErr
+		}
+
+		cat := NewCat(&s3, &con, nil, []string{"a.txt"})
+		if err := cat.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func Test_isBinary(t *testing.T) {
+	tests := []struct {
+		input  string
+		binary bool
+	}{
+		{"", false},
+		{"Hello\nWorld", false},
+		{"Héllo", false},
+		{"Hello\x00", true},
+		{"\xff\xfe", true},
+		{"Truncated \xc3", false},
+	}
+
+	for _, test := range tests {
+		if binary := isBinary([]byte(test.input)); binary != test.binary {
+			t.Fatalf("Unexpected result for %q: {Expected: %v, Actual: %v}", test.input, test.binary, binary)
+		}
+	}
+}
+
+func TestCatCommand_IsLongRunning(t *testing.T) {
+	cat := NewCat(nil, nil, nil, nil)
+
+	if cat.IsLongRunning() {
+		t.Fatal("Expected CatCommand to never be long running")
+	}
+}
+
+func TestNewCat(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	var in mockInputter
+	args := []string{"a.txt"}
+
+	cat := NewCat(&s3, &con, &in, args)
+	if cat.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on cat command: %v", cat.s3)
+	} else if cat.con != &con {
+		t.Fatalf("Unexpected Context stored on cat command: %v", cat.con)
+	} else if cat.in != &in {
+		t.Fatalf("Unexpected Inputter stored on cat command: %v", cat.in)
+	} else if cat.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on cat command: %v", cat.args)
+	}
+}
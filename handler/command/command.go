@@ -2,13 +2,19 @@
 package command
 
 import (
+	"io"
 	"os"
+
+	"github.com/KyleBanks/s3fs/client"
 )
 
 const (
 	// CmdLs lists directory contents based on the current context.
 	CmdLs = "ls"
 
+	// CmdTree renders a folder and its subfolders as a tree.
+	CmdTree = "tree"
+
 	// CmdCd changes directory.
 	CmdCd = "cd"
 
@@ -18,6 +24,51 @@ const (
 	// CmdPut uploads an object.
 	CmdPut = "put"
 
+	// CmdRm removes objects.
+	CmdRm = "rm"
+
+	// CmdCp copies objects.
+	CmdCp = "cp"
+
+	// CmdMv moves objects.
+	CmdMv = "mv"
+
+	// CmdMkdir creates folders.
+	CmdMkdir = "mkdir"
+
+	// CmdMb makes buckets.
+	CmdMb = "mb"
+
+	// CmdRb removes buckets.
+	CmdRb = "rb"
+
+	// CmdCat prints the contents of objects.
+	CmdCat = "cat"
+
+	// CmdHead prints the first lines of objects.
+	CmdHead = "head"
+
+	// CmdTail prints the last lines of objects.
+	CmdTail = "tail"
+
+	// CmdStat prints the metadata of objects and buckets.
+	CmdStat = "stat"
+
+	// CmdInfo is an alias of CmdStat.
+	CmdInfo = "info"
+
+	// CmdDu summarizes disk usage.
+	CmdDu = "du"
+
+	// CmdFind searches for objects matching a set of predicates.
+	CmdFind = "find"
+
+	// CmdSync mirrors local directories and Amazon S3 folders.
+	CmdSync = "sync"
+
+	// CmdResume resumes uploads and downloads that stopped before completing.
+	CmdResume = "resume"
+
 	// CmdPwd prints the present working directory.
 	CmdPwd = "pwd"
 
@@ -42,15 +93,39 @@ type Outputter interface {
 	Write(string)
 }
 
+// Inputter defines a type that can receive input from the user, one line at a time.
+type Inputter interface {
+	Scan() bool
+	Text() string
+}
+
 // S3Client defines an interface that communicates with Amazon S3.
 type S3Client interface {
 	LsBuckets() ([]string, error)
-	LsObjects(bucket, prefix string) ([]string, error)
+	LsObjects(bucket, prefix string, fn func([]client.Object) bool) error
+	LsDir(bucket, prefix string, fn func(folders []string, files []client.Object) bool) error
 
 	BucketExists(string) (bool, error)
 	ObjectExists(string, string) (bool, error)
 	PathExists(string, string) (bool, error)
+	HeadObject(string, string) (client.ObjectInfo, error)
+	GetBucketInfo(string) (client.BucketInfo, error)
+
+	OpenObject(string, string) (io.ReadCloser, error)
+	OpenObjectRange(string, string, int64, int64) (io.ReadCloser, error)
+	DownloadFile(string, string, string, client.DownloadOptions) error
+	UploadObject(string, string, *os.File, client.UploadOptions) (string, error)
+	PutObject(string, string, *os.File, client.UploadOptions) error
+	DeleteObject(string, string) error
+	DeleteObjects(string, []string) ([]client.DeleteError, error)
+	CopyObject(string, string, string, string) error
+	CreateFolder(string, string) error
+
+	CreateBucket(string, string) error
+	DeleteBucket(string) error
+	LsObjectVersions(bucket, prefix string, fn func([]client.ObjectVersion) bool) error
+	DeleteObjectVersions(string, []client.ObjectVersion) ([]client.DeleteError, error)
 
-	DownloadObject(string, string) (string, error)
-	UploadObject(string, string, *os.File) (string, error)
+	Transfers() ([]client.Transfer, error)
+	DiscardTransfer(client.Transfer) error
 }
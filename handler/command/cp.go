@@ -0,0 +1,184 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// cpArgsIndexSource indicates the expected argument index for the source to copy.
+	cpArgsIndexSource = 0
+
+	// cpArgsIndexDestination indicates the expected argument index for the copy destination.
+	cpArgsIndexDestination = 1
+
+	// cpFlagRecursive indicates that folders, and every object within them, should be copied.
+	cpFlagRecursive = "r"
+)
+
+// CpCommand copies objects server-side, within or across buckets.
+type CpCommand struct {
+	s3  S3Client
+	con *context.Context
+
+	args []string
+}
+
+// copyJob describes the objects to be copied from a source to a destination, where the source and destination
+// are keys for a single object, or prefixes for a folder.
+type copyJob struct {
+	srcBucket string
+	src       string
+	dstBucket string
+	dst       string
+	isFolder  bool
+}
+
+// Execute performs a 'cp' command by copying a source object, or folder when recursive, to a destination.
+func (cp CpCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(cp.args)
+	if len(flags.Args) < cpArgsIndexDestination+1 {
+		return errors.New("Missing source or destination.")
+	}
+
+	job, err := resolveCopy(cp.s3, cp.con, flags.Args[cpArgsIndexSource], flags.Args[cpArgsIndexDestination], flags.Has(cpFlagRecursive))
+	if err != nil {
+		return err
+	}
+
+	var count int
+	var copyErr error
+	err = job.each(cp.s3, func(src, dst string) bool {
+		if copyErr = cp.s3.CopyObject(job.srcBucket, src, job.dstBucket, dst); copyErr != nil {
+			return false
+		}
+
+		count++
+		out.Write(fmt.Sprintf("\nCopied: %v -> %v", displayPath([]string{job.srcBucket, src}), displayPath([]string{job.dstBucket, dst})))
+		return true
+	})
+	if err != nil {
+		return err
+	} else if copyErr != nil {
+		return copyErr
+	}
+
+	if job.isFolder {
+		out.Write(fmt.Sprintf("\nCopied %d object(s)", count))
+	}
+
+	return nil
+}
+
+// IsLongRunning returns true because 'cp' requires network operations.
+func (CpCommand) IsLongRunning() bool {
+	return true
+}
+
+// NewCp initializes and returns a CpCommand.
+func NewCp(s3 S3Client, con *context.Context, args []string) CpCommand {
+	return CpCommand{
+		s3:   s3,
+		con:  con,
+		args: args,
+	}
+}
+
+// resolveCopy calculates the source and destination of a copy, relative to the context, and validates that the
+// source exists.
+//
+// Following the semantics of a local filesystem, if the destination is an existing folder (or ends in the path
+// delimiter, for single objects), the source is copied into it using its own name. Folders may only be copied
+// when recursive, and never into themselves.
+func resolveCopy(s3 S3Client, con *context.Context, srcArg, dstArg string, recursive bool) (copyJob, error) {
+	srcPath := con.CalculatePath(srcArg)
+	if len(srcPath) == 0 {
+		return copyJob{}, errors.New("Cannot copy the root.")
+	}
+
+	dstPath := con.CalculatePath(dstArg)
+	if len(dstPath) == 0 {
+		return copyJob{}, errors.New("Missing destination bucket.")
+	}
+
+	var job copyJob
+	var srcKey, dstKey string
+	job.srcBucket, srcKey = splitKeyPath(srcPath)
+	job.dstBucket, dstKey = splitKeyPath(dstPath)
+
+	// Determine if the source is a file or folder.
+	if len(srcKey) > 0 {
+		if ok, err := s3.ObjectExists(job.srcBucket, srcKey); err != nil {
+			return copyJob{}, err
+		} else if ok {
+			job.src = srcKey
+		}
+	}
+
+	if len(job.src) == 0 {
+		_, prefix := splitFolderPath(srcPath)
+		if ok, err := folderExists(s3, job.srcBucket, prefix); err != nil {
+			return copyJob{}, err
+		} else if !ok {
+			return copyJob{}, errors.New("No such file or directory: " + displayPath(srcPath))
+		} else if !recursive {
+			return copyJob{}, fmt.Errorf("Source is a folder, use -r to copy it: %v", displayPath(srcPath))
+		}
+
+		job.src = prefix
+		job.isFolder = true
+	}
+
+	// Determine if the destination is an existing folder.
+	_, dstPrefix := splitFolderPath(dstPath)
+	dstExists, err := folderExists(s3, job.dstBucket, dstPrefix)
+	if err != nil {
+		return copyJob{}, err
+	} else if !dstExists && len(dstKey) == 0 {
+		return copyJob{}, errors.New("No such bucket: " + displayPath(dstPath))
+	}
+
+	name := srcPath[len(srcPath)-1]
+	switch {
+	case !job.isFolder && (dstExists || strings.HasSuffix(dstArg, context.PathDelimiter)):
+		job.dst = dstPrefix + name
+	case !job.isFolder:
+		job.dst = dstKey
+	case dstExists:
+		job.dst = dstPrefix + name + context.PathDelimiter
+	default:
+		job.dst = dstPrefix
+	}
+
+	if job.isFolder && job.srcBucket == job.dstBucket && strings.HasPrefix(job.dst, job.src) {
+		return copyJob{}, fmt.Errorf("Cannot copy a folder into itself: %v", displayPath(srcPath))
+	} else if job.srcBucket == job.dstBucket && job.src == job.dst {
+		return copyJob{}, fmt.Errorf("Source and destination are the same: %v", displayPath(srcPath))
+	}
+
+	return job, nil
+}
+
+// each provides the source and destination key of every object to be copied to fn, stopping early if fn
+// returns false.
+func (job copyJob) each(s3 S3Client, fn func(src, dst string) bool) error {
+	if !job.isFolder {
+		fn(job.src, job.dst)
+		return nil
+	}
+
+	return s3.LsObjects(job.srcBucket, job.src, func(objects []client.Object) bool {
+		for _, o := range objects {
+			if !fn(o.Key, job.dst+strings.TrimPrefix(o.Key, job.src)) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
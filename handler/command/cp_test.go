@@ -0,0 +1,141 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+func TestCpCommand_Execute(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {
+			{Key: "a.txt"},
+			{Key: "folder/b.txt"},
+			{Key: "folder/sub/c.txt"},
+			{Key: "other/d.txt"},
+		},
+		"bucket2": {
+			{Key: "existing/e.txt"},
+		},
+	}
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		// Single objects
+		{[]string{"a.txt", "b.txt"}, []string{"bucket/a.txt -> bucket/b.txt"}},
+		{[]string{"a.txt", "folder"}, []string{"bucket/a.txt -> bucket/folder/a.txt"}},
+		{[]string{"a.txt", "new/"}, []string{"bucket/a.txt -> bucket/new/a.txt"}},
+		{[]string{"folder/b.txt", "/bucket2"}, []string{"bucket/folder/b.txt -> bucket2/b.txt"}},
+		{[]string{"folder/b.txt", "/bucket2/renamed.txt"}, []string{"bucket/folder/b.txt -> bucket2/renamed.txt"}},
+
+		// Folders
+		{[]string{"-r", "folder", "new"}, []string{"bucket/folder/b.txt -> bucket/new/b.txt", "bucket/folder/sub/c.txt -> bucket/new/sub/c.txt"}},
+		{[]string{"-r", "folder", "other"}, []string{"bucket/folder/b.txt -> bucket/other/folder/b.txt", "bucket/folder/sub/c.txt -> bucket/other/folder/sub/c.txt"}},
+		{[]string{"-r", "folder/", "/bucket2/existing/"}, []string{"bucket/folder/b.txt -> bucket2/existing/folder/b.txt", "bucket/folder/sub/c.txt -> bucket2/existing/folder/sub/c.txt"}},
+		{[]string{"-r", "/bucket2", "/bucket/other"}, []string{"bucket2/existing/e.txt -> bucket/other/bucket2/existing/e.txt"}},
+	}
+
+	for _, test := range tests {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		var copied []string
+		s3.copyObjectCallback = func(srcBucket, srcKey, dstBucket, dstKey string) error {
+			copied = append(copied, srcBucket+"/"+srcKey+" -> "+dstBucket+"/"+dstKey)
+			return nil
+		}
+
+		cp := NewCp(&s3, &con, test.args)
+		if err := cp.Execute(&out); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.args, err)
+		}
+
+		if strings.Join(copied, ",") != strings.Join(test.expected, ",") {
+			t.Fatalf("Unexpected copies for %v: {Expected: %v, Actual: %v}", test.args, test.expected, copied)
+		} else if len(out.output) < len(test.expected) || !strings.Contains(out.output[0], "Copied: /") {
+			t.Fatalf("Expected copies to be output for %v: %v", test.args, out.output)
+		}
+	}
+}
+
+func TestCpCommand_Execute_invalid(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "a.txt"}, {Key: "folder/b.txt"}},
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"a.txt"},
+		{"/", "bucket"},
+		{"a.txt", "/"},
+		{"fake.txt", "b.txt"},
+		{"folder", "new"},
+		{"a.txt", "/fake"},
+		{"-r", "folder", "folder/sub"},
+		{"-r", "/bucket", "/bucket/folder"},
+		{"a.txt", "/bucket/"},
+	} {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.copyObjectCallback = func(srcBucket, srcKey, dstBucket, dstKey string) error {
+			t.Fatalf("CopyObject should not be called for invalid args: %v", args)
+			return nil
+		}
+
+		cp := NewCp(&s3, &con, args)
+		if err := cp.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+
+	// S3 error
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		mockErr := errors.New("Mock Error")
+
+		s3.copyObjectCallback = func(srcBucket, srcKey, dstBucket, dstKey string) error {
+			return mockErr
+		}
+
+		cp := NewCp(&s3, &con, []string{"a.txt", "b.txt"})
+		if err := cp.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestCpCommand_IsLongRunning(t *testing.T) {
+	cp := NewCp(nil, nil, nil)
+
+	if !cp.IsLongRunning() {
+		t.Fatal("Expected CpCommand to always be long running")
+	}
+}
+
+func TestNewCp(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	args := []string{"src", "dst"}
+
+	cp := NewCp(&s3, &con, args)
+	if cp.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on cp command: %v", cp.s3)
+	} else if cp.con != &con {
+		t.Fatalf("Unexpected Context stored on cp command: %v", cp.con)
+	} else if cp.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on cp command: %v", cp.args)
+	}
+}
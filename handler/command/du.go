@@ -0,0 +1,208 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// duFlagHumanReadable indicates that sizes should be output in a human-readable format.
+	duFlagHumanReadable = "h"
+
+	// duFlagDepth indicates the depth of sub-folders to output individual totals for.
+	duFlagDepth = "d"
+
+	// duRootDepth is the default depth at the root, so that each bucket is summarized.
+	duRootDepth = 1
+)
+
+// DuCommand summarizes the disk usage of a folder, bucket, or every bucket.
+type DuCommand struct {
+	s3  S3Client
+	con *context.Context
+
+	args []string
+}
+
+// duTotal is the total size and number of objects within a folder or storage class.
+type duTotal struct {
+	size  int64
+	count int
+}
+
+// add includes an object in the total.
+func (t *duTotal) add(o client.Object) {
+	t.size += o.Size
+	t.count++
+}
+
+// Execute performs a 'du' command by summing the size of every object within the target, and outputting the total
+// along with a breakdown per storage class and, optionally, per sub-folder.
+func (du DuCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(du.args, duFlagDepth)
+
+	var target string
+	if len(flags.Args) > 0 {
+		target = flags.Args[0]
+	}
+	p := du.con.CalculatePath(target)
+
+	depth := 0
+	if len(p) == 0 {
+		depth = duRootDepth
+	}
+	if flags.Has(duFlagDepth) {
+		var err error
+		if depth, err = strconv.Atoi(flags.Value(duFlagDepth)); err != nil || depth < 0 {
+			return fmt.Errorf("Invalid depth: %v", flags.Value(duFlagDepth))
+		}
+	}
+
+	// Determine the buckets to summarize, validating the target folder when not at the root.
+	var buckets []string
+	var prefix string
+	if len(p) == 0 {
+		var err error
+		if buckets, err = du.s3.LsBuckets(); err != nil {
+			return err
+		}
+	} else {
+		buckets = p[:1]
+		_, prefix = splitFolderPath(p)
+		if ok, err := folderExists(du.s3, p[0], prefix); err != nil {
+			return err
+		} else if !ok {
+			return errors.New("No such file or directory: " + displayPath(p))
+		}
+	}
+
+	var total duTotal
+	folders := make(map[string]*duTotal)
+	classes := make(map[string]*duTotal)
+
+	for _, bucket := range buckets {
+		// Every bucket is output at the root, including those without any objects.
+		if len(p) == 0 && depth > 0 {
+			folders[displayPath([]string{bucket})+context.PathDelimiter] = &duTotal{}
+		}
+
+		err := du.s3.LsObjects(bucket, prefix, func(objects []client.Object) bool {
+			for _, o := range objects {
+				total.add(o)
+				addTo(classes, o.StorageClass, o)
+
+				// Add the object to each of its folders, relative to the target, up to the depth requested.
+				rel := strings.Split(strings.TrimPrefix(o.Key, prefix), context.PathDelimiter)
+				rel = rel[:len(rel)-1]
+				if len(p) == 0 {
+					rel = append([]string{bucket}, rel...)
+				}
+
+				for level := 1; level <= depth && level <= len(rel); level++ {
+					folder := append(append([]string{}, p...), rel[:level]...)
+					addTo(folders, displayPath(folder)+context.PathDelimiter, o)
+				}
+			}
+
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Output each sub-folder, followed by the total and the breakdown by storage class.
+	var lines, classLines [][2]string
+	for _, name := range sortedKeys(folders) {
+		lines = append(lines, du.line(flags, *folders[name], name))
+	}
+
+	name := displayPath(p)
+	if len(p) > 0 {
+		name += context.PathDelimiter
+	}
+	lines = append(lines, du.line(flags, total, name))
+
+	for _, class := range sortedKeys(classes) {
+		classLines = append(classLines, du.line(flags, *classes[class], class))
+	}
+
+	width := duWidth(append(append([][2]string{}, lines...), classLines...))
+	for _, l := range lines {
+		out.Write(fmt.Sprintf("\n%*v  %v", width, l[0], l[1]))
+	}
+
+	if len(classLines) > 0 {
+		out.Write("\n\nBy storage class:")
+		for _, l := range classLines {
+			out.Write(fmt.Sprintf("\n%*v  %v", width, l[0], l[1]))
+		}
+	}
+
+	return nil
+}
+
+// line returns the formatted size and description of a total.
+func (du DuCommand) line(flags util.Flags, t duTotal, name string) [2]string {
+	size := strconv.FormatInt(t.size, 10)
+	if flags.Has(duFlagHumanReadable) {
+		size = util.HumanSize(t.size)
+	}
+
+	return [2]string{size, fmt.Sprintf("%v (%d object(s))", name, t.count)}
+}
+
+// IsLongRunning returns true because 'du' requires network operations.
+func (DuCommand) IsLongRunning() bool {
+	return true
+}
+
+// NewDu initializes and returns a DuCommand.
+func NewDu(s3 S3Client, con *context.Context, args []string) DuCommand {
+	return DuCommand{
+		s3:   s3,
+		con:  con,
+		args: args,
+	}
+}
+
+// addTo includes an object in the named total, creating it if necessary.
+func addTo(totals map[string]*duTotal, name string, o client.Object) {
+	t, ok := totals[name]
+	if !ok {
+		t = &duTotal{}
+		totals[name] = t
+	}
+
+	t.add(o)
+}
+
+// sortedKeys returns the names of the totals provided, in lexical order.
+func sortedKeys(totals map[string]*duTotal) []string {
+	names := make([]string, 0, len(totals))
+	for name := range totals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// duWidth returns the width of the widest size in the lines provided, so that sizes can be right-aligned.
+func duWidth(lines [][2]string) int {
+	var width int
+	for _, l := range lines {
+		if len(l[0]) > width {
+			width = len(l[0])
+		}
+	}
+
+	return width
+}
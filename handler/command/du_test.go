@@ -0,0 +1,167 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+func TestDuCommand_Execute(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {
+			{Key: "a.txt", Size: 100, StorageClass: "STANDARD"},
+			{Key: "folder/b.txt", Size: 2048, StorageClass: "STANDARD"},
+			{Key: "folder/sub/c.txt", Size: 1000, StorageClass: "GLACIER"},
+			{Key: "folder/sub/deeper/d.txt", Size: 1, StorageClass: "GLACIER"},
+			{Key: "other/e.txt", Size: 10, StorageClass: "STANDARD"},
+		},
+		"bucket2": {
+			{Key: "f.txt", Size: 5, StorageClass: "STANDARD_IA"},
+		},
+		"empty": {},
+	}
+
+	tests := []struct {
+		path     string
+		args     []string
+		expected []string
+	}{
+		{"bucket", []string{"folder"}, []string{
+			"\n3049  /bucket/folder/ (3 object(s))",
+			"\n\nBy storage class:",
+			"\n1001  GLACIER (2 object(s))",
+			"\n2048  STANDARD (1 object(s))",
+		}},
+		{"bucket", []string{"-d", "1"}, []string{
+			"\n3049  /bucket/folder/ (3 object(s))",
+			"\n  10  /bucket/other/ (1 object(s))",
+			"\n3159  /bucket/ (5 object(s))",
+			"\n\nBy storage class:",
+			"\n1001  GLACIER (2 object(s))",
+			"\n2158  STANDARD (3 object(s))",
+		}},
+		{"bucket/folder", []string{"-h", "-d", "5"}, []string{
+			"\n1001  /bucket/folder/sub/ (2 object(s))",
+			"\n   1  /bucket/folder/sub/deeper/ (1 object(s))",
+			"\n3.0K  /bucket/folder/ (3 object(s))",
+			"\n\nBy storage class:",
+			"\n1001  GLACIER (2 object(s))",
+			"\n2.0K  STANDARD (1 object(s))",
+		}},
+		{"", nil, []string{
+			"\n3159  /bucket/ (5 object(s))",
+			"\n   5  /bucket2/ (1 object(s))",
+			"\n   0  /empty/ (0 object(s))",
+			"\n3164  / (6 object(s))",
+			"\n\nBy storage class:",
+			"\n1001  GLACIER (2 object(s))",
+			"\n2158  STANDARD (3 object(s))",
+			"\n   5  STANDARD_IA (1 object(s))",
+		}},
+		{"", []string{"-d", "0"}, []string{
+			"\n3164  / (6 object(s))",
+			"\n\nBy storage class:",
+			"\n1001  GLACIER (2 object(s))",
+			"\n2158  STANDARD (3 object(s))",
+			"\n   5  STANDARD_IA (1 object(s))",
+		}},
+	}
+
+	for _, test := range tests {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath(test.path)
+
+		du := NewDu(&s3, &con, test.args)
+		if err := du.Execute(&out); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.args, err)
+		}
+
+		expected := strings.Join(test.expected, "")
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected output for %v in %v: {Expected: %q, Actual: %q}", test.args, test.path, expected, output)
+		}
+	}
+}
+
+func TestDuCommand_Execute_invalid(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "a.txt"}},
+	}
+
+	for _, args := range [][]string{
+		{"fake"},
+		{"/fake"},
+		{"-d", "abc"},
+		{"-d", "-1"},
+	} {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.lsObjectsCallback = func(bucket, prefix string, fn func([]client.Object) bool) error {
+			t.Fatalf("LsObjects should not be called for invalid args: %v", args)
+			return nil
+		}
+
+		du := NewDu(&s3, &con, args)
+		if err := du.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+
+	// S3 errors
+	{
+		mockErr := errors.New("Mock Error")
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+
+		s3.lsBucketsCallback = func() ([]string, error) {
+			return nil, mockErr
+		}
+
+		du := NewDu(&s3, &con, nil)
+		if err := du.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+
+		s3 = newMockS3Listing(buckets)
+		s3.lsObjectsCallback = func(bucket, prefix string, fn func([]client.Object) bool) error {
+			return mockErr
+		}
+
+		du = NewDu(&s3, &con, nil)
+		if err := du.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestDuCommand_IsLongRunning(t *testing.T) {
+	du := NewDu(nil, nil, nil)
+
+	if !du.IsLongRunning() {
+		t.Fatal("Expected DuCommand to always be long running")
+	}
+}
+
+func TestNewDu(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	args := []string{"folder"}
+
+	du := NewDu(&s3, &con, args)
+	if du.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on du command: %v", du.s3)
+	} else if du.con != &con {
+		t.Fatalf("Unexpected Context stored on du command: %v", du.con)
+	} else if du.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on du command: %v", du.args)
+	}
+}
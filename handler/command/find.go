@@ -0,0 +1,380 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+const (
+	// findPredicateName matches objects whose name matches a glob pattern.
+	findPredicateName = "-name"
+
+	// findPredicateSize matches objects larger than (+N), smaller than (-N), or exactly (N) a size.
+	findPredicateSize = "-size"
+
+	// findPredicateMtime matches objects modified less than (-N), more than (+N), or exactly (N) days ago.
+	findPredicateMtime = "-mtime"
+
+	// findPredicateClass matches objects in a storage class.
+	findPredicateClass = "-class"
+
+	// findActionDelete removes the matching objects, in batches per bucket, after confirmation.
+	findActionDelete = "-delete"
+
+	// findActionGet downloads the matching objects with 'get', to an optional local directory.
+	findActionGet = "-get"
+
+	// day is the duration of a single day, as used by the mtime predicate.
+	day = time.Hour * 24
+)
+
+// findSizeUnits are the multipliers of the size suffixes supported by the size predicate.
+var findSizeUnits = map[byte]int64{
+	'c': 1,
+	'K': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+	'T': 1 << 40,
+}
+
+// FindCommand searches folders for objects matching a set of predicates, and optionally performs an action
+// on each match.
+type FindCommand struct {
+	s3  S3Client
+	con *context.Context
+	in  Inputter
+
+	args []string
+}
+
+// findQuery is the parsed form of a FindCommand's arguments.
+type findQuery struct {
+	paths      []string
+	predicates []func(client.Object) bool
+	action     string
+	actionArg  string
+}
+
+// Execute performs a 'find' command by walking each target folder and outputting every object that matches all
+// of the predicates provided.
+//
+// When an action is provided, the matching objects are instead passed to the corresponding command.
+func (find FindCommand) Execute(out Outputter) error {
+	q, err := parseFindQuery(find.args, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Validate every target before walking any of them.
+	targets := make([][]string, len(q.paths))
+	for i, arg := range q.paths {
+		p := find.con.CalculatePath(arg)
+		if len(p) > 0 {
+			bucket, prefix := splitFolderPath(p)
+			if ok, err := folderExists(find.s3, bucket, prefix); err != nil {
+				return err
+			} else if !ok {
+				return errors.New("No such file or directory: " + displayPath(p))
+			}
+		}
+		targets[i] = p
+	}
+
+	var matches []string
+	keys := make(map[string][]string)
+	for _, p := range targets {
+		err := find.walk(p, func(bucket string, o client.Object) {
+			for _, pred := range q.predicates {
+				if !pred(o) {
+					return
+				}
+			}
+
+			match := displayPath([]string{bucket, o.Key})
+			matches = append(matches, match)
+			keys[bucket] = append(keys[bucket], o.Key)
+			out.Write("\n" + match)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case len(matches) == 0:
+		return nil
+	case q.action == findActionDelete:
+		return find.remove(out, len(matches), keys)
+	case q.action == findActionGet:
+		for _, m := range matches {
+			if err := NewGet(find.s3, find.con, []string{m, q.actionArg}).Execute(out); err != nil {
+				return err
+			}
+		}
+		out.Write(fmt.Sprintf("\nDownloaded %d object(s)", len(matches)))
+	}
+
+	return nil
+}
+
+// remove deletes the matching keys of each bucket in batches, after prompting the user for confirmation, and
+// outputs any objects that could not be removed.
+//
+// The matches were found by listing, so they are deleted without first checking that each exists.
+func (find FindCommand) remove(out Outputter, count int, keys map[string][]string) error {
+	if !confirm(out, find.in, fmt.Sprintf("Remove %d object(s)?", count)) {
+		out.Write("\nNothing removed.")
+		return nil
+	}
+
+	// Buckets are removed from in order of name, so that the output is consistent.
+	buckets := make([]string, 0, len(keys))
+	for bucket := range keys {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+
+	var removed int
+	var failed []client.DeleteError
+	for _, bucket := range buckets {
+		f, err := find.s3.DeleteObjects(bucket, keys[bucket])
+		if err != nil {
+			return err
+		}
+
+		removed += len(keys[bucket]) - len(f)
+		failed = append(failed, f...)
+	}
+
+	for _, f := range failed {
+		out.Write("\nFailed to remove: " + f.Error())
+	}
+
+	out.Write(fmt.Sprintf("\nRemoved %d object(s)", removed))
+	if len(failed) > 0 {
+		return fmt.Errorf("Failed to remove %d object(s)", len(failed))
+	}
+
+	return nil
+}
+
+// walk provides every object within a folder to fn, where an empty path walks every bucket.
+//
+// Folder marker objects are skipped.
+func (find FindCommand) walk(p []string, fn func(bucket string, o client.Object)) error {
+	buckets := p
+	var prefix string
+	if len(p) == 0 {
+		var err error
+		if buckets, err = find.s3.LsBuckets(); err != nil {
+			return err
+		}
+	} else {
+		buckets = p[:1]
+		_, prefix = splitFolderPath(p)
+	}
+
+	for _, bucket := range buckets {
+		err := find.s3.LsObjects(bucket, prefix, func(objects []client.Object) bool {
+			for _, o := range objects {
+				if !strings.HasSuffix(o.Key, context.PathDelimiter) {
+					fn(bucket, o)
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsLongRunning returns true unless matches are to be deleted, as the loading indicator would otherwise interfere
+// with the confirmation prompt.
+func (find FindCommand) IsLongRunning() bool {
+	for _, arg := range find.args {
+		if arg == findActionDelete {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewFind initializes and returns a FindCommand.
+func NewFind(s3 S3Client, con *context.Context, in Inputter, args []string) FindCommand {
+	return FindCommand{
+		s3:   s3,
+		con:  con,
+		in:   in,
+		args: args,
+	}
+}
+
+// parseFindQuery parses the paths, predicates and action of a 'find' command, relative to the time provided.
+//
+// Paths precede the predicates, and default to the current folder.
+func parseFindQuery(args []string, now time.Time) (findQuery, error) {
+	var q findQuery
+
+	// Ignore empty arguments, such as those produced by repeated spaces.
+	nonEmpty := make([]string, 0, len(args))
+	for _, arg := range args {
+		if len(arg) > 0 {
+			nonEmpty = append(nonEmpty, arg)
+		}
+	}
+	args = nonEmpty
+
+	i := 0
+	for ; i < len(args) && !strings.HasPrefix(args[i], "-"); i++ {
+		q.paths = append(q.paths, args[i])
+	}
+	if len(q.paths) == 0 {
+		q.paths = []string{"."}
+	}
+
+	// value returns the argument following a predicate, advancing past it.
+	value := func(name string) (string, error) {
+		if i+1 >= len(args) {
+			return "", fmt.Errorf("Missing value for %v", name)
+		}
+		i++
+		return args[i], nil
+	}
+
+	for ; i < len(args); i++ {
+		name := args[i]
+
+		switch name {
+		case findPredicateName:
+			pattern, err := value(name)
+			if err != nil {
+				return q, err
+			} else if _, err := path.Match(pattern, ""); err != nil {
+				return q, fmt.Errorf("Invalid pattern: %v", pattern)
+			}
+
+			q.predicates = append(q.predicates, func(o client.Object) bool {
+				ok, _ := path.Match(pattern, path.Base(o.Key))
+				return ok
+			})
+
+		case findPredicateSize:
+			v, err := value(name)
+			if err != nil {
+				return q, err
+			}
+
+			cmp, size, err := parseFindSize(v)
+			if err != nil {
+				return q, err
+			}
+
+			q.predicates = append(q.predicates, func(o client.Object) bool {
+				return compareSign(o.Size, size) == cmp
+			})
+
+		case findPredicateMtime:
+			v, err := value(name)
+			if err != nil {
+				return q, err
+			}
+
+			cmp, days, err := parseFindNumber(v)
+			if err != nil {
+				return q, fmt.Errorf("Invalid age: %v", v)
+			}
+
+			q.predicates = append(q.predicates, func(o client.Object) bool {
+				age := int64(now.Sub(o.LastModified) / day)
+				return compareSign(age, days) == cmp
+			})
+
+		case findPredicateClass:
+			class, err := value(name)
+			if err != nil {
+				return q, err
+			}
+
+			q.predicates = append(q.predicates, func(o client.Object) bool {
+				return strings.EqualFold(o.StorageClass, class)
+			})
+
+		case findActionDelete, findActionGet:
+			if len(q.action) > 0 {
+				return q, errors.New("Only one action may be provided.")
+			}
+			q.action = name
+
+			// The destination of downloads is optional.
+			if name == findActionGet && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				i++
+				q.actionArg = args[i]
+			}
+
+		default:
+			return q, fmt.Errorf("Unknown predicate: %v", name)
+		}
+	}
+
+	return q, nil
+}
+
+// parseFindSize parses the comparison and size, in bytes, of a size predicate such as +100M.
+func parseFindSize(v string) (int, int64, error) {
+	unit := int64(1)
+	if len(v) > 0 {
+		if u, ok := findSizeUnits[v[len(v)-1]]; ok {
+			unit = u
+			v = v[:len(v)-1]
+		}
+	}
+
+	cmp, n, err := parseFindNumber(v)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid size: %v", v)
+	}
+
+	return cmp, n * unit, nil
+}
+
+// parseFindNumber parses a number that may be prefixed with + or -, returning the comparison that a value must
+// have with the number to match: 1 for greater than, -1 for less than, and 0 for equal.
+func parseFindNumber(v string) (int, int64, error) {
+	cmp := 0
+	if strings.HasPrefix(v, "+") {
+		cmp, v = 1, v[1:]
+	} else if strings.HasPrefix(v, "-") {
+		cmp, v = -1, v[1:]
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, 0, errors.New("Invalid number: " + v)
+	}
+
+	return cmp, n, nil
+}
+
+// compareSign returns 1 if a is greater than b, -1 if a is less than b, and 0 if they are equal.
+func compareSign(a, b int64) int {
+	switch {
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	}
+
+	return 0
+}
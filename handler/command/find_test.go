@@ -0,0 +1,293 @@
+package command
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+// findBuckets returns the objects used by the find tests, relative to the current time.
+func findBuckets() map[string][]client.Object {
+	now := time.Now()
+	return map[string][]client.Object{
+		"bucket": {
+			{Key: "a.parquet", Size: 200 << 20, StorageClass: "GLACIER", LastModified: now.Add(-day)},
+			{Key: "folder/", Size: 0, StorageClass: "STANDARD", LastModified: now},
+			{Key: "folder/b.parquet", Size: 50 << 20, StorageClass: "STANDARD", LastModified: now.Add(-day * 2)},
+			{Key: "folder/c.txt", Size: 100, StorageClass: "STANDARD", LastModified: now.Add(-day * 30)},
+			{Key: "folder/sub/d.parquet", Size: 300 << 20, StorageClass: "GLACIER", LastModified: now.Add(-day * 10)},
+		},
+		"bucket2": {
+			{Key: "e.parquet", Size: 1024, StorageClass: "STANDARD_IA", LastModified: now.Add(-time.Hour)},
+		},
+	}
+}
+
+func TestFindCommand_Execute(t *testing.T) {
+	tests := []struct {
+		path     string
+		args     []string
+		expected []string
+	}{
+		{"bucket", nil, []string{"/bucket/a.parquet", "/bucket/folder/b.parquet", "/bucket/folder/c.txt", "/bucket/folder/sub/d.parquet"}},
+		{"bucket", []string{"folder"}, []string{"/bucket/folder/b.parquet", "/bucket/folder/c.txt", "/bucket/folder/sub/d.parquet"}},
+		{"bucket", []string{"-name", "*.parquet"}, []string{"/bucket/a.parquet", "/bucket/folder/b.parquet", "/bucket/folder/sub/d.parquet"}},
+		{"bucket", []string{"-name", "*.parquet", "-size", "+100M"}, []string{"/bucket/a.parquet", "/bucket/folder/sub/d.parquet"}},
+		{"bucket", []string{"-size", "-1K"}, []string{"/bucket/folder/c.txt"}},
+		{"bucket", []string{"-size", "100c"}, []string{"/bucket/folder/c.txt"}},
+		{"bucket", []string{"-mtime", "-7"}, []string{"/bucket/a.parquet", "/bucket/folder/b.parquet"}},
+		{"bucket", []string{"-mtime", "+7"}, []string{"/bucket/folder/c.txt", "/bucket/folder/sub/d.parquet"}},
+		{"bucket", []string{"-mtime", "2"}, []string{"/bucket/folder/b.parquet"}},
+		{"bucket", []string{"-class", "glacier", "-mtime", "+7"}, []string{"/bucket/folder/sub/d.parquet"}},
+		{"", []string{"-name", "*.parquet", "-mtime", "-1"}, []string{"/bucket2/e.parquet"}},
+		{"", []string{"bucket/folder", "/bucket2", "-size", "+1000"}, []string{"/bucket/folder/b.parquet", "/bucket/folder/sub/d.parquet", "/bucket2/e.parquet"}},
+		{"bucket", []string{"-name", "*.csv"}, nil},
+		{"bucket", []string{"", "folder", "", "-name", "", "*.txt", ""}, []string{"/bucket/folder/c.txt"}},
+	}
+
+	for _, test := range tests {
+		s3 := newMockS3Listing(findBuckets())
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath(test.path)
+
+		find := NewFind(&s3, &con, &mockInputter{}, test.args)
+		if err := find.Execute(&out); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.args, err)
+		}
+
+		var expected string
+		for _, e := range test.expected {
+			expected += "\n" + e
+		}
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected output for %v: {Expected: %q, Actual: %q}", test.args, expected, output)
+		}
+	}
+}
+
+func TestFindCommand_Execute_delete(t *testing.T) {
+	// Confirmed
+	{
+		var deleted []string
+		s3 := newMockS3Listing(findBuckets())
+		s3.objectExistsCallback = func(bucket, key string) (bool, error) {
+			t.Fatalf("Unexpected HEAD request for %v/%v", bucket, key)
+			return false, nil
+		}
+		s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+			for _, key := range keys {
+				deleted = append(deleted, bucket+"/"+key)
+			}
+			return nil, nil
+		}
+		var con context.Context
+		var out mockOutputter
+		in := mockInputter{lines: []string{"y"}}
+
+		find := NewFind(&s3, &con, &in, []string{"-size", "+1000", "-delete"})
+		if err := find.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"bucket/a.parquet", "bucket/folder/b.parquet", "bucket/folder/sub/d.parquet", "bucket2/e.parquet"}
+		if !reflect.DeepEqual(deleted, expected) {
+			t.Fatalf("Unexpected deleted objects: {Expected: %v, Actual: %v}", expected, deleted)
+		}
+
+		output := strings.Join(out.output, "")
+		if !strings.Contains(output, "\n/bucket2/e.parquet") || !strings.Contains(output, "Remove 4 object(s)?") ||
+			!strings.HasSuffix(output, "\nRemoved 4 object(s)") {
+			t.Fatalf("Unexpected output: %q", output)
+		}
+	}
+
+	// Failures
+	{
+		s3 := newMockS3Listing(findBuckets())
+		s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+			return []client.DeleteError{{Key: keys[0], Code: "AccessDenied", Message: "Access Denied"}}, nil
+		}
+		var con context.Context
+		var out mockOutputter
+		in := mockInputter{lines: []string{"y"}}
+		con.UpdatePath("bucket")
+
+		find := NewFind(&s3, &con, &in, []string{"-class", "GLACIER", "-delete"})
+		if err := find.Execute(&out); err == nil {
+			t.Fatal("Expected error when objects fail to be removed")
+		}
+
+		output := strings.Join(out.output, "")
+		if !strings.Contains(output, "\nFailed to remove: ") || !strings.HasSuffix(output, "\nRemoved 1 object(s)") {
+			t.Fatalf("Unexpected output: %q", output)
+		}
+	}
+
+	// S3 error
+	{
+		s3 := newMockS3Listing(findBuckets())
+		mockErr := errors.New("Mock Error")
+		s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+			return nil, mockErr
+		}
+		var con context.Context
+		var out mockOutputter
+		in := mockInputter{lines: []string{"y"}}
+		con.UpdatePath("bucket")
+
+		find := NewFind(&s3, &con, &in, []string{"-class", "GLACIER", "-delete"})
+		if err := find.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+
+	// Declined
+	{
+		s3 := newMockS3Listing(findBuckets())
+		s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+			t.Fatalf("Unexpected delete of %v/%v", bucket, keys)
+			return nil, nil
+		}
+		var con context.Context
+		var out mockOutputter
+		in := mockInputter{lines: []string{"n"}}
+		con.UpdatePath("bucket")
+
+		find := NewFind(&s3, &con, &in, []string{"-class", "GLACIER", "-delete"})
+		if err := find.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindCommand_Execute_get(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s3 := newMockS3Listing(findBuckets())
+	s3.downloadFileCallback = func(bucket, key, path string, opts client.DownloadOptions) error {
+		return ioutil.WriteFile(path, []byte(bucket+"/"+key), 0644)
+	}
+	var con context.Context
+	var out mockOutputter
+	con.UpdatePath("bucket")
+
+	find := NewFind(&s3, &con, &mockInputter{}, []string{"-name", "*.parquet", "-mtime", "-7", "-get", dir})
+	if err := find.Execute(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, contents := range map[string]string{
+		"a.parquet": "bucket/a.parquet",
+		"b.parquet": "bucket/folder/b.parquet",
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		} else if string(b) != contents {
+			t.Fatalf("Unexpected contents of %v: {Expected: %v, Actual: %v}", name, contents, string(b))
+		}
+	}
+
+	expected := "\n/bucket/a.parquet\n/bucket/folder/b.parquet\nDownloaded 2 object(s)"
+	if output := strings.Join(out.output, ""); output != expected {
+		t.Fatalf("Unexpected output: {Expected: %q, Actual: %q}", expected, output)
+	}
+}
+
+func TestFindCommand_Execute_invalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"fake"},
+		{"/fake"},
+		{"-name"},
+		{"-name", "[a-"},
+		{"-size", "abc"},
+		{"-size", "+10X"},
+		{"-mtime", "+abc"},
+		{"-class"},
+		{"-unknown"},
+		{"-delete", "-get"},
+		{"-name", "*.txt", "folder"},
+	} {
+		s3 := newMockS3Listing(findBuckets())
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.lsObjectsCallback = func(bucket, prefix string, fn func([]client.Object) bool) error {
+			t.Fatalf("LsObjects should not be called for invalid args: %v", args)
+			return nil
+		}
+
+		find := NewFind(&s3, &con, &mockInputter{}, args)
+		if err := find.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+
+	// S3 errors
+	{
+		mockErr := errors.New("Mock Error")
+		s3 := newMockS3Listing(findBuckets())
+		var con context.Context
+		var out mockOutputter
+
+		s3.lsBucketsCallback = func() ([]string, error) {
+			return nil, mockErr
+		}
+
+		find := NewFind(&s3, &con, &mockInputter{}, nil)
+		if err := find.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+
+		s3 = newMockS3Listing(findBuckets())
+		s3.lsObjectsCallback = func(bucket, prefix string, fn func([]client.Object) bool) error {
+			return mockErr
+		}
+
+		find = NewFind(&s3, &con, &mockInputter{}, nil)
+		if err := find.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestFindCommand_IsLongRunning(t *testing.T) {
+	if !NewFind(nil, nil, nil, []string{"-name", "*.txt"}).IsLongRunning() {
+		t.Fatal("Expected find to be long running")
+	}
+	if NewFind(nil, nil, nil, []string{"-name", "*.txt", "-delete"}).IsLongRunning() {
+		t.Fatal("Expected find -delete not to be long running, as it prompts for confirmation")
+	}
+}
+
+func TestNewFind(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	var in mockInputter
+	args := []string{"-name", "*.txt"}
+
+	find := NewFind(&s3, &con, &in, args)
+	if find.s3 != &s3 {
+		t.Fatalf("Unexpected S3Client: {Expected: %v, Actual: %v}", &s3, find.s3)
+	} else if find.con != &con {
+		t.Fatalf("Unexpected Context: {Expected: %v, Actual: %v}", &con, find.con)
+	} else if find.in != &in {
+		t.Fatalf("Unexpected Inputter: {Expected: %v, Actual: %v}", &in, find.in)
+	} else if !reflect.DeepEqual(find.args, args) {
+		t.Fatalf("Unexpected args: {Expected: %v, Actual: %v}", args, find.args)
+	}
+}
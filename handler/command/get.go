@@ -1,12 +1,17 @@
 package command
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/KyleBanks/s3fs/client"
 	"github.com/KyleBanks/s3fs/handler/command/context"
 	"github.com/KyleBanks/s3fs/handler/command/util"
 )
@@ -17,6 +22,22 @@ const (
 
 	// getArgsIndexDestination indicates the expected argument index for the destination file location.
 	getArgsIndexDestination = 1
+
+	// getFlagRecursive indicates that a folder, and every object within it, should be downloaded.
+	getFlagRecursive = "r"
+
+	// getFlagPartSize provides the size of each byte range when downloading large objects in ranges, such as '64M'.
+	getFlagPartSize = "part-size"
+
+	// getFlagConcurrency provides the number of ranges of each large object downloaded at once.
+	getFlagConcurrency = "concurrency"
+
+	// getConcurrency is the number of objects downloaded at once when downloading a folder.
+	getConcurrency = 8
+
+	// multipartETagSeparator separates the checksum of a multipart object's ETag from its part count. The ETag
+	// of such an object is not the MD5 of its contents.
+	multipartETagSeparator = "-"
 )
 
 // GetCommand downloads a remote file.
@@ -25,51 +46,210 @@ type GetCommand struct {
 	con *context.Context
 
 	args []string
+
+	interrupt chan os.Signal
+}
+
+// getResult is the outcome of downloading a single object within a folder.
+type getResult struct {
+	path     string
+	size     int64
+	skipped  bool
+	isFolder bool
+	err      error
 }
 
 // Execute performs a 'get' by downloading a remote file to a local destination.
+//
+// When recursive, a folder is downloaded by recreating its tree within the destination. Large objects are
+// downloaded in ranges, and downloads that are interrupted can be resumed by performing the same 'get' again.
 func (get GetCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(get.args, getFlagPartSize, getFlagConcurrency)
+	var opts client.DownloadOptions
+	var err error
+	if opts.PartSize, opts.Concurrency, err = partOptions(flags, getFlagPartSize, getFlagConcurrency); err != nil {
+		return err
+	}
+
 	// Get the target to download from the input arguments.
-	if len(get.args) < getArgsIndexTarget+1 {
+	if len(flags.Args) < getArgsIndexTarget+1 {
 		return errors.New("Missing target file.")
 	}
-	target := get.args[getArgsIndexTarget]
+	target := flags.Args[getArgsIndexTarget]
+
+	var dstArg string
+	if len(flags.Args) >= getArgsIndexDestination+1 {
+		dstArg = flags.Args[getArgsIndexDestination]
+	}
+
+	cancel, stop := cancelOnInterrupt(get.interrupt)
+	defer stop()
+	opts.Cancel = cancel
 
 	// Calculate the S3 object path.
 	path := get.con.CalculatePath(target)
+	if flags.Has(getFlagRecursive) {
+		if len(path) == 0 {
+			return errors.New("Cannot download the root.")
+		}
+
+		bucket, prefix := splitFolderPath(path)
+		if ok, err := folderExists(get.s3, bucket, prefix); err != nil {
+			return err
+		} else if ok {
+			return get.getFolder(out, path, dstArg, opts)
+		}
+	}
+
 	if len(path) <= 1 {
 		return fmt.Errorf("Target is not a file: %v", strings.Join(path, context.PathDelimiter))
 	}
 
-	// Download the object.
-	f, err := get.s3.DownloadObject(path[0], strings.Join(path[1:], context.PathDelimiter))
+	// Get the destination to put the downloaded file.
+	dst, err := get.absDestination(dstArg, path[len(path)-1])
 	if err != nil {
 		return err
 	}
 
-	// Get the destination to put the downloaded file.
-	dst, err := get.absDestination(path[len(path)-1])
+	// Download the object.
+	return get.s3.DownloadFile(path[0], strings.Join(path[1:], context.PathDelimiter), dst, opts)
+}
+
+// getFolder downloads every object within a folder concurrently, skipping those that already exist locally with
+// the same contents, and outputs a summary of the download.
+//
+// Following the semantics of 'cp -r', if the destination is an existing directory the folder is downloaded into
+// it using its own name, otherwise the destination becomes the folder.
+func (get GetCommand) getFolder(out Outputter, path []string, dstArg string, opts client.DownloadOptions) error {
+	bucket, prefix := splitFolderPath(path)
+
+	if len(dstArg) == 0 {
+		dstArg = "."
+	}
+	dst, err := util.AbsPath(dstArg)
 	if err != nil {
 		return err
 	}
+	if dstInfo, _ := os.Stat(dst); dstInfo != nil {
+		if !dstInfo.IsDir() {
+			return errors.New("Destination is not a directory: " + dst)
+		}
+		dst = filepath.Join(dst, path[len(path)-1])
+	}
 
-	// Move the file to the proper destination.
-	if err := os.Rename(f, dst); err != nil {
-		return err
+	objects := make(chan client.Object)
+	results := make(chan getResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < getConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for o := range objects {
+				results <- get.download(bucket, prefix, o, dst, opts)
+			}
+		}()
+	}
+
+	var lsErr error
+	go func() {
+		lsErr = get.s3.LsObjects(bucket, prefix, func(page []client.Object) bool {
+			for _, o := range page {
+				// Stop queueing objects once interrupted.
+				select {
+				case <-opts.Cancel:
+					return false
+				case objects <- o:
+				}
+			}
+			return true
+		})
+
+		close(objects)
+		wg.Wait()
+		close(results)
+	}()
+
+	var downloaded, skipped, failed int
+	var size int64
+	for r := range results {
+		switch {
+		case r.err == client.ErrDownloadInterrupted:
+		case r.err != nil:
+			failed++
+			out.Write(fmt.Sprintf("\nFailed to download: %v: %v", r.path, r.err))
+		case r.isFolder:
+		case r.skipped:
+			skipped++
+		default:
+			downloaded++
+			size += r.size
+		}
+	}
+
+	if lsErr != nil {
+		return lsErr
+	}
+
+	out.Write(fmt.Sprintf("\nDownloaded %d object(s) (%v), skipped %d unchanged object(s): %v%v -> %v",
+		downloaded, util.HumanSize(size), skipped, displayPath(path), context.PathDelimiter, dst))
+
+	select {
+	case <-opts.Cancel:
+		return client.ErrDownloadInterrupted
+	default:
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Failed to download %d object(s)", failed)
 	}
 
 	return nil
 }
 
+// download downloads an object within a folder to its relative location within the local destination, unless
+// an identical file already exists there.
+//
+// Folder marker objects are created as empty directories.
+func (get GetCommand) download(bucket, prefix string, o client.Object, dst string, opts client.DownloadOptions) getResult {
+	res := getResult{
+		path: displayPath([]string{bucket, o.Key}),
+		size: o.Size,
+	}
+
+	local, err := localKeyPath(dst, strings.TrimPrefix(o.Key, prefix))
+	if err != nil {
+		res.err = err
+		return res
+	}
+
+	if strings.HasSuffix(o.Key, context.PathDelimiter) {
+		res.isFolder = true
+		res.err = os.MkdirAll(local, 0755)
+		return res
+	}
+
+	if res.skipped, res.err = localFileMatches(local, o); res.skipped || res.err != nil {
+		return res
+	}
+
+	if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+		res.err = err
+		return res
+	}
+
+	res.err = get.s3.DownloadFile(bucket, o.Key, local, opts)
+	return res
+}
+
 // absDestination returns the absolute path of the destination argument where the downloaded object should be placed.
 //
 // If the destination argument is not set, the current working directory will be used.
 // If the destination argument points to a directory, the defaultName provided will be used as the file name.
-func (get GetCommand) absDestination(defaultName string) (string, error) {
+func (get GetCommand) absDestination(dst, defaultName string) (string, error) {
 	// Use the default name if the destination argument was not provided.
-	dst := defaultName
-	if len(get.args) >= getArgsIndexDestination+1 && len(get.args[getArgsIndexDestination]) > 0 {
-		dst = get.args[getArgsIndexDestination]
+	if len(dst) == 0 {
+		dst = defaultName
 	}
 
 	// Convert to the absolute path.
@@ -99,3 +279,46 @@ func NewGet(s3 S3Client, con *context.Context, args []string) GetCommand {
 		args: args,
 	}
 }
+
+// localFileMatches returns true if the local file at path has the same size and contents as an object.
+//
+// The contents are compared using the MD5 checksum of the file, so objects uploaded in multiple parts, whose
+// ETag is not a checksum of their contents, never match.
+func localFileMatches(path string, o client.Object) (bool, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	} else if info.IsDir() {
+		return false, errors.New("Destination is a directory: " + path)
+	}
+
+	etag := strings.Trim(o.ETag, `"`)
+	if info.Size() != o.Size || len(etag) == 0 || strings.Contains(etag, multipartETagSeparator) {
+		return false, nil
+	}
+
+	sum, err := fileMD5(path)
+	if err != nil {
+		return false, err
+	}
+
+	return sum == etag, nil
+}
+
+// fileMD5 returns the hex encoded MD5 checksum of the contents of a local file.
+func fileMD5(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
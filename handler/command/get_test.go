@@ -1,14 +1,22 @@
 package command
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"errors"
 	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/KyleBanks/s3fs/client"
 	"github.com/KyleBanks/s3fs/handler/command/context"
 )
 
@@ -33,20 +41,18 @@ func TestGetCommand_Execute(t *testing.T) {
 	folder := "folder"
 	key := "file.txt"
 	target := bucket + context.PathDelimiter + folder + context.PathDelimiter + key
-	fileContents := "test file @ " + string(time.Now().UnixNano())
+	fileContents := "test file @ " + strconv.FormatInt(time.Now().UnixNano(), 10)
 
 	var s3 mockS3Client
 	var out mockOutputter
 	var con context.Context
 
-	s3.downloadObjectCallback = func(b, k string) (string, error) {
+	s3.downloadFileCallback = func(b, k, path string, opts client.DownloadOptions) error {
 		if b != bucket || k != folder+context.PathDelimiter+key {
-			t.Fatalf("Unexpected bucket/key provided to DownloadObject(%v, %v)", b, k)
+			t.Fatalf("Unexpected bucket/key provided to DownloadFile(%v, %v)", b, k)
 		}
 
-		f, _ := ioutil.TempFile("", "")
-		f.WriteString(fileContents)
-		return f.Name(), nil
+		return ioutil.WriteFile(path, []byte(fileContents), 0644)
 	}
 
 	// Positive: With destination as file
@@ -100,17 +106,15 @@ func TestGetCommand_Execute(t *testing.T) {
 		var con context.Context
 		var s3 mockS3Client
 
-		// Update context to point to a new path, and the downloadObjectCallback to
+		// Update context to point to a new path, and the downloadFileCallback to
 		// use the custom path.
 		con.UpdatePath("bucket2/folder2/subfolder")
-		s3.downloadObjectCallback = func(b, k string) (string, error) {
+		s3.downloadFileCallback = func(b, k, path string, opts client.DownloadOptions) error {
 			if b != "bucket2" || k != "folder2/subfolder"+context.PathDelimiter+key {
-				t.Fatalf("Unexpected bucket/key provided to DownloadObject(%v, %v)", b, k)
+				t.Fatalf("Unexpected bucket/key provided to DownloadFile(%v, %v)", b, k)
 			}
 
-			f, _ := ioutil.TempFile("", "")
-			f.WriteString(fileContents)
-			return f.Name(), nil
+			return ioutil.WriteFile(path, []byte(fileContents), 0644)
 		}
 
 		// Create a destination file to write the downloaded object to.
@@ -140,8 +144,8 @@ func TestGetCommand_Execute(t *testing.T) {
 		// Shadow the s3 client mock interface, and set the callback to return an error.
 		var s3 mockS3Client
 		mockErr := errors.New("Mock Err")
-		s3.downloadObjectCallback = func(b, k string) (string, error) {
-			return "", mockErr
+		s3.downloadFileCallback = func(b, k, path string, opts client.DownloadOptions) error {
+			return mockErr
 		}
 
 		// Perform the download.
@@ -150,6 +154,311 @@ func TestGetCommand_Execute(t *testing.T) {
 			t.Fatalf("Expected the mock error to be bubbled up: %v", err)
 		}
 	}
+
+	// Negative: Interrupted
+	{
+		var s3 mockS3Client
+		s3.downloadFileCallback = func(b, k, path string, opts client.DownloadOptions) error {
+			<-opts.Cancel
+			return client.ErrDownloadInterrupted
+		}
+
+		get := NewGet(&s3, &con, []string{target})
+		get.interrupt = make(chan os.Signal, 1)
+		get.interrupt <- os.Interrupt
+		if err := get.Execute(&out); err != client.ErrDownloadInterrupted {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+}
+
+func TestGetCommand_Execute_recursive(t *testing.T) {
+	// etag returns the ETag of an object with the contents provided.
+	etag := func(contents string) string {
+		sum := md5.Sum([]byte(contents))
+		return `"` + hex.EncodeToString(sum[:]) + `"`
+	}
+
+	// The contents of each object are its bucket and key.
+	buckets := map[string][]client.Object{
+		"bucket": {
+			{Key: "a.txt", Size: 12, ETag: etag("bucket/a.txt")},
+			{Key: "folder/", Size: 0, ETag: etag("")},
+			{Key: "folder/b.txt", Size: 19, ETag: etag("bucket/folder/b.txt")},
+			{Key: "folder/c.txt", Size: 19, ETag: etag("bucket/folder/c.txt")},
+			{Key: "folder/empty/", Size: 0, ETag: etag("")},
+			{Key: "folder/sub/d.txt", Size: 23, ETag: etag("bucket/folder/sub/d.txt")},
+			{Key: "folder/sub/e.txt", Size: 23, ETag: `"abc-2"`},
+		},
+	}
+
+	// mockS3 returns a client that records the keys downloaded.
+	mockS3 := func(downloaded *[]string) mockS3Client {
+		var mu sync.Mutex
+		s3 := newMockS3Listing(buckets)
+		s3.downloadFileCallback = func(bucket, key, path string, opts client.DownloadOptions) error {
+			mu.Lock()
+			*downloaded = append(*downloaded, key)
+			mu.Unlock()
+
+			return ioutil.WriteFile(path, []byte(bucket+"/"+key), 0644)
+		}
+		return s3
+	}
+
+	// validate ensures each local file has the contents of its object.
+	validate := func(dir string, keys ...string) {
+		for _, key := range keys {
+			b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(key, "folder/"))))
+			if err != nil {
+				t.Fatal(err)
+			} else if string(b) != "bucket/"+key {
+				t.Fatalf("Unexpected contents of %v: %v", key, string(b))
+			}
+		}
+	}
+
+	// Positive: Into an existing directory
+	{
+		dir, _ := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir)
+
+		var downloaded []string
+		s3 := mockS3(&downloaded)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		get := NewGet(&s3, &con, []string{"-r", "folder", dir})
+		if err := get.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		dst := filepath.Join(dir, "folder")
+		validate(dst, "folder/b.txt", "folder/c.txt", "folder/sub/d.txt", "folder/sub/e.txt")
+		if info, err := os.Stat(filepath.Join(dst, "empty")); err != nil || !info.IsDir() {
+			t.Fatalf("Expected empty folder to be created: %v", err)
+		}
+
+		expected := "\nDownloaded 4 object(s) (84), skipped 0 unchanged object(s): /bucket/folder/ -> " + dst
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+
+	// Positive: Skipping unchanged files
+	{
+		dir, _ := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir)
+		dst := filepath.Join(dir, "folder")
+
+		// Unchanged
+		os.MkdirAll(filepath.Join(dst, "sub"), 0755)
+		ioutil.WriteFile(filepath.Join(dst, "b.txt"), []byte("bucket/folder/b.txt"), 0644)
+
+		// Same size, different contents
+		ioutil.WriteFile(filepath.Join(dst, "c.txt"), []byte("bucket/folder/x.txt"), 0644)
+
+		// Same contents, but a multipart ETag
+		ioutil.WriteFile(filepath.Join(dst, "sub", "e.txt"), []byte("bucket/folder/sub/e.txt"), 0644)
+
+		var downloaded []string
+		s3 := mockS3(&downloaded)
+		var con context.Context
+		var out mockOutputter
+
+		get := NewGet(&s3, &con, []string{"/bucket/folder/", dir, "-r"})
+		if err := get.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		validate(dst, "folder/b.txt", "folder/c.txt", "folder/sub/d.txt", "folder/sub/e.txt")
+
+		sort.Strings(downloaded)
+		expected := []string{"folder/c.txt", "folder/sub/d.txt", "folder/sub/e.txt"}
+		if !reflect.DeepEqual(downloaded, expected) {
+			t.Fatalf("Unexpected downloads: {Expected: %v, Actual: %v}", expected, downloaded)
+		}
+
+		output := strings.Join(out.output, "")
+		if !strings.HasPrefix(output, "\nDownloaded 3 object(s) (65), skipped 1 unchanged object(s)") {
+			t.Fatalf("Unexpected output: %q", output)
+		}
+	}
+
+	// Positive: Into a new directory
+	{
+		dir, _ := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir)
+		dst := filepath.Join(dir, "dst")
+
+		var downloaded []string
+		s3 := mockS3(&downloaded)
+		var con context.Context
+		var out mockOutputter
+
+		get := NewGet(&s3, &con, []string{"-r", "/bucket/folder", dst})
+		if err := get.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+		validate(dst, "folder/b.txt", "folder/c.txt", "folder/sub/d.txt", "folder/sub/e.txt")
+	}
+
+	// Positive: A file is downloaded as usual
+	{
+		dir, _ := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir)
+
+		var downloaded []string
+		s3 := mockS3(&downloaded)
+		var con context.Context
+		var out mockOutputter
+
+		get := NewGet(&s3, &con, []string{"-r", "/bucket/a.txt", dir})
+		if err := get.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+		validate(dir, "a.txt")
+	}
+
+	// Negative: Invalid targets
+	for _, args := range [][]string{
+		{"-r", "/"},
+		{"-r", "/fake/folder", os.TempDir()},
+	} {
+		var downloaded []string
+		s3 := mockS3(&downloaded)
+		s3.downloadFileCallback = func(bucket, key, path string, opts client.DownloadOptions) error {
+			return errors.New("Not found")
+		}
+		var con context.Context
+		var out mockOutputter
+
+		get := NewGet(&s3, &con, args)
+		if err := get.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+
+	// Negative: Destination is a file
+	{
+		f, _ := ioutil.TempFile("", "")
+		f.Close()
+		defer os.Remove(f.Name())
+
+		var downloaded []string
+		s3 := mockS3(&downloaded)
+		var con context.Context
+		var out mockOutputter
+
+		get := NewGet(&s3, &con, []string{"-r", "/bucket/folder", f.Name()})
+		if err := get.Execute(&out); err == nil {
+			t.Fatal("Expected error when the destination is a file")
+		} else if len(downloaded) > 0 {
+			t.Fatalf("Unexpected downloads: %v", downloaded)
+		}
+	}
+
+	// Negative: S3 errors
+	{
+		dir, _ := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir)
+
+		mockErr := errors.New("Mock Error")
+		var downloaded []string
+		s3 := mockS3(&downloaded)
+		var con context.Context
+		var out mockOutputter
+
+		s3.lsObjectsCallback = func(bucket, prefix string, fn func([]client.Object) bool) error {
+			return mockErr
+		}
+
+		get := NewGet(&s3, &con, []string{"-r", "/bucket/folder", dir})
+		if err := get.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+
+		// A failed download does not prevent the others from completing.
+		s3 = mockS3(&downloaded)
+		s3.downloadFileCallback = func(bucket, key, path string, opts client.DownloadOptions) error {
+			if key == "folder/c.txt" {
+				return mockErr
+			}
+
+			return ioutil.WriteFile(path, []byte(bucket+"/"+key), 0644)
+		}
+
+		get = NewGet(&s3, &con, []string{"-r", "/bucket/folder", dir})
+		if err := get.Execute(&out); err == nil {
+			t.Fatal("Expected error when a download fails")
+		}
+		validate(filepath.Join(dir, "folder"), "folder/b.txt", "folder/sub/d.txt")
+
+		output := strings.Join(out.output, "")
+		if !strings.Contains(output, "\nFailed to download: /bucket/folder/c.txt: Mock Error") {
+			t.Fatalf("Unexpected output: %q", output)
+		}
+	}
+}
+
+func TestGetCommand_Execute_ranges(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "")
+	defer os.RemoveAll(dir)
+
+	// Options
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+
+		s3.downloadFileCallback = func(bucket, key, path string, opts client.DownloadOptions) error {
+			if opts.PartSize != 64*1024*1024 || opts.Concurrency != 8 || opts.Cancel == nil {
+				t.Fatalf("Unexpected DownloadOptions: %v", opts)
+			}
+			return nil
+		}
+
+		get := NewGet(&s3, &con, []string{"/bucket/file.txt", dir, "--part-size", "64M", "--concurrency=8"})
+		if err := get.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Defaults
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+
+		s3.downloadFileCallback = func(bucket, key, path string, opts client.DownloadOptions) error {
+			if opts.PartSize != 0 || opts.Concurrency != 0 {
+				t.Fatalf("Unexpected DownloadOptions: %v", opts)
+			}
+			return nil
+		}
+
+		get := NewGet(&s3, &con, []string{"/bucket/file.txt", dir})
+		if err := get.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Invalid options
+	for _, args := range [][]string{
+		{"/bucket/file.txt", "--part-size", "1M"},
+		{"/bucket/file.txt", "--part-size", "abc"},
+		{"/bucket/file.txt", "--concurrency", "0"},
+	} {
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+
+		get := NewGet(&s3, &con, args)
+		if err := get.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
 }
 
 func TestGetCommand_absDestination(t *testing.T) {
@@ -176,7 +485,11 @@ func TestGetCommand_absDestination(t *testing.T) {
 		get := NewGet(nil, nil, test.args)
 
 		// Get the destination.
-		res, err := get.absDestination(test.s3Path[len(test.s3Path)-1])
+		var dst string
+		if len(test.args) > getArgsIndexDestination {
+			dst = test.args[getArgsIndexDestination]
+		}
+		res, err := get.absDestination(dst, test.s3Path[len(test.s3Path)-1])
 		if err != nil {
 			t.Fatal(err)
 		}
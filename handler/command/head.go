@@ -0,0 +1,158 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// lineFlagCount indicates the number of lines to print with 'head' and 'tail'.
+	lineFlagCount = "n"
+
+	// defaultLineCount is the number of lines printed by 'head' and 'tail' when no count is provided.
+	defaultLineCount = 10
+
+	// rangeChunkSize is the number of bytes requested at a time by 'head' and 'tail'.
+	rangeChunkSize = 64 * 1024
+)
+
+// lineTarget is an object whose lines are printed by a 'head' or 'tail' command.
+type lineTarget struct {
+	path []string
+	size int64
+}
+
+// HeadCommand prints the first lines of objects.
+type HeadCommand struct {
+	s3  S3Client
+	con *context.Context
+
+	args []string
+}
+
+// Execute performs a 'head' command by reading each target object forward in chunks, using ranged requests, until
+// enough lines have been found to print.
+func (head HeadCommand) Execute(out Outputter) error {
+	n, targets, err := resolveLineTargets(head.s3, head.con, util.ParseFlags(head.args, lineFlagCount))
+	if err != nil {
+		return err
+	}
+
+	for _, t := range targets {
+		if len(targets) > 1 {
+			out.Write("\n==> " + displayPath(t.path) + " <==")
+		}
+
+		if err := head.print(out, t.path, t.size, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// print outputs the first n lines of an object of the size provided.
+func (head HeadCommand) print(out Outputter, p []string, size int64, n int) error {
+	bucket, key := splitKeyPath(p)
+
+	out.Write("\n")
+
+	var lines int
+	for start := int64(0); start < size && lines < n; start += rangeChunkSize {
+		b, err := readRange(head.s3, bucket, key, start, size)
+		if err != nil {
+			return err
+		}
+
+		// Truncate the chunk after the final newline required.
+		for i, c := range b {
+			if c != '\n' {
+				continue
+			}
+
+			lines++
+			if lines == n {
+				b = b[:i+1]
+				break
+			}
+		}
+
+		out.Write(string(b))
+	}
+
+	return nil
+}
+
+// IsLongRunning returns false, as the contents are output as they are received and the loading indicator would
+// otherwise interfere with them.
+func (HeadCommand) IsLongRunning() bool {
+	return false
+}
+
+// NewHead initializes and returns a HeadCommand.
+func NewHead(s3 S3Client, con *context.Context, args []string) HeadCommand {
+	return HeadCommand{
+		s3:   s3,
+		con:  con,
+		args: args,
+	}
+}
+
+// resolveLineTargets parses the line count and resolves the target objects of a 'head' or 'tail' command,
+// validating that each target exists.
+func resolveLineTargets(s3 S3Client, con *context.Context, flags util.Flags) (int, []lineTarget, error) {
+	if len(flags.Args) == 0 {
+		return 0, nil, errors.New("Missing target file.")
+	}
+
+	n, err := lineCount(flags)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	targets := make([]lineTarget, len(flags.Args))
+	for i, arg := range flags.Args {
+		p, info, err := headFile(s3, con, arg)
+		if err != nil {
+			return 0, nil, err
+		}
+		targets[i] = lineTarget{path: p, size: info.Size}
+	}
+
+	return n, targets, nil
+}
+
+// lineCount returns the number of lines to print for a 'head' or 'tail' command.
+func lineCount(flags util.Flags) (int, error) {
+	if !flags.Has(lineFlagCount) {
+		return defaultLineCount, nil
+	}
+
+	n, err := strconv.Atoi(flags.Value(lineFlagCount))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("Invalid line count: %v", flags.Value(lineFlagCount))
+	}
+
+	return n, nil
+}
+
+// readRange reads a chunk of up to rangeChunkSize bytes from an object, beginning at start and ending before limit.
+func readRange(s3 S3Client, bucket, key string, start, limit int64) ([]byte, error) {
+	end := start + rangeChunkSize
+	if end > limit {
+		end = limit
+	}
+
+	r, err := s3.OpenObjectRange(bucket, key, start, end-1)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
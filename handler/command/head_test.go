@@ -0,0 +1,170 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+// mockLineContents returns the contents of an object with the number of lines provided, large enough to require
+// multiple range requests.
+func mockLineContents(lines int) string {
+	var contents []string
+	for i := 0; i < lines; i++ {
+		contents = append(contents, fmt.Sprintf("line %05d\n", i))
+	}
+	return strings.Join(contents, "")
+}
+
+// mockObjectRanges sets the HeadObject and OpenObjectRange callbacks of a mockS3Client to serve the contents of
+// each key in a bucket, and returns a pointer to the number of bytes requested.
+func mockObjectRanges(s3 *mockS3Client, contents map[string]string) *int64 {
+	var requested int64
+
+	s3.headObjectCallback = func(bucket, key string) (client.ObjectInfo, error) {
+		c, ok := contents[bucket+"/"+key]
+		if !ok {
+			return client.ObjectInfo{}, client.ErrObjectNotFound
+		}
+		return client.ObjectInfo{Key: key, Size: int64(len(c))}, nil
+	}
+	s3.openObjectRangeCallback = func(bucket, key string, start, end int64) (io.ReadCloser, error) {
+		c := contents[bucket+"/"+key]
+		if start < 0 || end >= int64(len(c)) || start > end {
+			return nil, fmt.Errorf("Invalid range for %v: %v-%v", key, start, end)
+		}
+
+		requested += end - start + 1
+		return ioutil.NopCloser(strings.NewReader(c[start : end+1])), nil
+	}
+
+	return &requested
+}
+
+func TestHeadCommand_Execute(t *testing.T) {
+	large := mockLineContents(20000)
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "large.log"}, {Key: "small.txt"}, {Key: "empty.txt"}},
+	}
+	contents := map[string]string{
+		"bucket/large.log": large,
+		"bucket/small.txt": "a\nb\nc",
+		"bucket/empty.txt": "",
+	}
+
+	tests := []struct {
+		args      []string
+		expected  string
+		maxLength int64
+	}{
+		{[]string{"small.txt"}, "\na\nb\nc", 5},
+		{[]string{"-n", "2", "small.txt"}, "\na\nb\n", 5},
+		{[]string{"-n", "0", "small.txt"}, "\n", 0},
+		{[]string{"empty.txt"}, "\n", 0},
+		{[]string{"large.log"}, "\n" + large[:110], rangeChunkSize},
+		{[]string{"-n", "10000", "large.log"}, "\n" + large[:110000], 2 * rangeChunkSize},
+		{[]string{"-n", "99999", "large.log"}, "\n" + large, int64(len(large))},
+		{[]string{"-n1", "small.txt", "/bucket/large.log"}, "\n==> /bucket/small.txt <==\na\n\n==> /bucket/large.log <==\nline 00000\n", 5 + rangeChunkSize},
+	}
+
+	for _, test := range tests {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		requested := mockObjectRanges(&s3, contents)
+
+		head := NewHead(&s3, &con, test.args)
+		if err := head.Execute(&out); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.args, err)
+		}
+
+		if output := strings.Join(out.output, ""); output != test.expected {
+			t.Fatalf("Unexpected output for %v: {Expected: %q, Actual: %q}", test.args, test.expected, output)
+		} else if *requested > test.maxLength {
+			t.Fatalf("Unexpected bytes requested for %v: {Expected: <= %v, Actual: %v}", test.args, test.maxLength, *requested)
+		}
+	}
+}
+
+func TestHeadCommand_Execute_invalid(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "a.txt"}, {Key: "folder/b.txt"}},
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"folder"},
+		{"fake.txt"},
+		{"-n", "abc", "a.txt"},
+		{"-n", "-1", "a.txt"},
+	} {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.objectExistsCallback = func(bucket, key string) (bool, error) {
+			t.Fatalf("ObjectExists should not be called, as HeadObject determines whether the object exists: %v", args)
+			return false, nil
+		}
+		s3.headObjectCallback = func(bucket, key string) (client.ObjectInfo, error) {
+			return client.ObjectInfo{}, client.ErrObjectNotFound
+		}
+
+		head := NewHead(&s3, &con, args)
+		if err := head.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+
+	// S3 error
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		mockErr := errors.New("Mock Error")
+
+		s3.headObjectCallback = func(bucket, key string) (client.ObjectInfo, error) {
+			return client.ObjectInfo{Key: key, Size: 10}, nil
+		}
+		s3.openObjectRangeCallback = func(bucket, key string, start, end int64) (io.ReadCloser, error) {
+			return nil, mockErr
+		}
+
+		head := NewHead(&s3, &con, []string{"a.txt"})
+		if err := head.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestHeadCommand_IsLongRunning(t *testing.T) {
+	head := NewHead(nil, nil, nil)
+
+	if head.IsLongRunning() {
+		t.Fatal("Expected HeadCommand to never be long running")
+	}
+}
+
+func TestNewHead(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	args := []string{"a.txt"}
+
+	head := NewHead(&s3, &con, args)
+	if head.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on head command: %v", head.s3)
+	} else if head.con != &con {
+		t.Fatalf("Unexpected Context stored on head command: %v", head.con)
+	} else if head.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on head command: %v", head.args)
+	}
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"os"
+	"os/signal"
+)
+
+// cancelOnInterrupt returns a channel that is closed if the command is interrupted, along with a function that
+// stops listening for interrupts.
+//
+// If interrupt is nil, the command is interrupted by os.Interrupt.
+func cancelOnInterrupt(interrupt chan os.Signal) (<-chan struct{}, func()) {
+	notify := interrupt == nil
+	if notify {
+		interrupt = make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+	}
+
+	cancel := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-interrupt:
+			close(cancel)
+		case <-done:
+		}
+	}()
+
+	return cancel, func() {
+		if notify {
+			signal.Stop(interrupt)
+		}
+		close(done)
+	}
+}
+
+// isCancelled returns true if cancel has been closed.
+func isCancelled(cancel <-chan struct{}) bool {
+	select {
+	case <-cancel:
+		return true
+	default:
+		return false
+	}
+}
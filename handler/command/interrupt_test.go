@@ -0,0 +1,36 @@
+package command
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCancelOnInterrupt(t *testing.T) {
+	// Interrupted
+	{
+		interrupt := make(chan os.Signal, 1)
+		cancel, stop := cancelOnInterrupt(interrupt)
+		defer stop()
+
+		interrupt <- os.Interrupt
+		select {
+		case <-cancel:
+		case <-time.After(time.Second):
+			t.Fatal("Expected cancel to be closed when interrupted")
+		}
+	}
+
+	// Stopped
+	{
+		interrupt := make(chan os.Signal, 1)
+		cancel, stop := cancelOnInterrupt(interrupt)
+		stop()
+
+		select {
+		case <-cancel:
+			t.Fatal("Expected cancel to remain open when stopped without an interrupt")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
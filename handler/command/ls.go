@@ -1,10 +1,16 @@
 package command
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"path"
+	"sort"
 	"strings"
 
+	"github.com/KyleBanks/s3fs/client"
 	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
 )
 
 const (
@@ -16,66 +22,371 @@ const (
 
 	// filePrefix is the prefix used when outputting file names.
 	filePrefix = ""
+
+	// lsFlagLong indicates that objects should be listed with their metadata.
+	lsFlagLong = "l"
+
+	// lsFlagHuman indicates that object sizes should be human-readable.
+	lsFlagHuman = "h"
+
+	// lsFlagTime indicates that entries should be sorted by modification time, newest first.
+	lsFlagTime = "t"
+
+	// lsFlagSize indicates that entries should be sorted by size, largest first.
+	lsFlagSize = "S"
+
+	// lsFlagReverse indicates that the sort order should be reversed.
+	lsFlagReverse = "r"
+
+	// lsFlagRecursive indicates that the contents of every subfolder should be listed.
+	lsFlagRecursive = "R"
+
+	// lsCurrentDir is the header used for the pwd in a recursive listing.
+	lsCurrentDir = "."
+
+	// lsGlobChars are the characters that indicate an argument is a glob pattern.
+	lsGlobChars = "*?["
+
+	// lsEscapeChar escapes a glob character so that it is matched literally.
+	lsEscapeChar = "\\"
+
+	// lsFolderClass is output in place of the storage class of a folder in a long listing.
+	lsFolderClass = "DIR"
+
+	// lsTimeFormat is the format of the last-modified time of an object in a long listing.
+	lsTimeFormat = "2006-01-02 15:04"
 )
 
 // LsCommand simulates 'ls' functionality.
 type LsCommand struct {
 	s3  S3Client
 	con *context.Context
+
+	args []string
+}
+
+// lsEntry is a single folder or file to be output by an LsCommand, with a key relative to the listed folder.
+type lsEntry struct {
+	obj      client.Object
+	isFolder bool
+}
+
+// lsTarget is a resolved location to be listed by an LsCommand.
+type lsTarget struct {
+	isRoot  bool
+	bucket  string
+	prefix  string
+	pattern string
 }
 
 // Execute performs a 'ls' command by printing the buckets/objects in the pwd based on the underlying context.
+//
+// Zero or more paths may be provided to list instead of the pwd, each of which may contain a glob pattern in its
+// final element (ie. 'logs/2024-*') to filter the output. When multiple paths are provided, or the listing is
+// recursive, each listing is preceded by a header containing the path.
 func (ls LsCommand) Execute(out Outputter) error {
-	var res []string
-	var err error
-	var prefix string
-	var isBucketList bool
+	flags := util.ParseFlags(ls.args)
+	recursive := flags.Has(lsFlagRecursive)
+
+	args := flags.Args
+	if len(args) == 0 {
+		args = []string{""}
+	}
+
+	// Resolve and validate every path before listing any of them.
+	targets := make([]lsTarget, len(args))
+	for i, arg := range args {
+		t, err := ls.resolve(arg)
+		if err != nil {
+			return err
+		}
+		targets[i] = t
+	}
+
+	for i, t := range targets {
+		header := args[i]
+		if len(header) == 0 {
+			header = lsCurrentDir
+		}
+
+		if len(targets) > 1 || recursive {
+			if i > 0 {
+				out.Write("\n")
+			}
+			out.Write("\n" + header + ":")
+		}
+
+		if t.isRoot {
+			buckets, err := ls.lsBuckets(out, flags, t.pattern)
+			if err != nil {
+				return err
+			} else if !recursive {
+				continue
+			}
+
+			// Recursively list the contents of each bucket.
+			for _, b := range buckets {
+				out.Write("\n\n" + ls.sectionHeader(header, b) + ":")
+				folders, err := ls.lsObjects(out, flags, b, "", "")
+				if err != nil {
+					return err
+				} else if err := ls.lsRecursive(out, flags, b, "", ls.sectionHeader(header, b), folders); err != nil {
+					return err
+				}
+			}
+			continue
+		}
 
-	// Determine which type of 'ls' to perform based on the context.
-	if ls.con.IsRoot() {
-		isBucketList = true
-		res, err = ls.s3.LsBuckets()
-	} else {
-		// If we have a prefix, store it and provide it to the LsObject command.
-		if len(ls.con.PathWithoutBucket()) > 0 {
-			prefix = ls.con.PathWithoutBucket() + context.PathDelimiter
+		folders, err := ls.lsObjects(out, flags, t.bucket, t.prefix, t.pattern)
+		if err != nil {
+			return err
+		} else if recursive {
+			if err := ls.lsRecursive(out, flags, t.bucket, t.prefix, header, folders); err != nil {
+				return err
+			}
 		}
+	}
+
+	return nil
+}
+
+// lsRecursive outputs a section for each folder, and recursively each of its subfolders, in a bucket.
+//
+// Section headers are the base header followed by the folder path relative to the base prefix.
+func (ls LsCommand) lsRecursive(out Outputter, flags util.Flags, bucket, base, header string, folders []string) error {
+	for _, f := range folders {
+		out.Write("\n\n" + ls.sectionHeader(header, strings.TrimPrefix(f, base)) + ":")
 
-		res, err = ls.s3.LsObjects(ls.con.Bucket(), prefix)
+		subfolders, err := ls.lsObjects(out, flags, bucket, f, "")
+		if err != nil {
+			return err
+		} else if err := ls.lsRecursive(out, flags, bucket, base, header, subfolders); err != nil {
+			return err
+		}
 	}
 
-	// Sanity.
+	return nil
+}
+
+// sectionHeader joins a base header and a relative folder path for a recursive listing section.
+func (LsCommand) sectionHeader(header, rel string) string {
+	return strings.TrimSuffix(header, context.PathDelimiter) + context.PathDelimiter + strings.TrimSuffix(rel, context.PathDelimiter)
+}
+
+// resolve calculates the location to list for a path argument, relative to the context, and validates that it
+// exists.
+//
+// Paths to a single file are resolved as their parent folder, with a pattern matching only the file.
+func (ls LsCommand) resolve(arg string) (lsTarget, error) {
+	// Separate the folder to list from the pattern to filter by.
+	dir, pattern, err := ls.splitPattern(arg)
 	if err != nil {
-		return err
+		return lsTarget{}, err
 	}
 
-	// Group and filter the output.
-	cache := make(map[string]bool)
-	for _, f := range res {
-		// Remove the prefix if applicable.
-		if len(prefix) > len(context.PathDelimiter) && strings.Contains(f, prefix) {
-			f = strings.Replace(f, prefix, "", 1)
+	s3Path := ls.con.CalculatePath(dir)
+	if len(s3Path) == 0 {
+		return lsTarget{isRoot: true, pattern: pattern}, nil
+	}
+
+	t := lsTarget{pattern: pattern}
+	t.bucket, t.prefix = splitFolderPath(s3Path)
+
+	// The context path has already been validated, so only explicitly provided folders need to be checked.
+	if len(dir) == 0 {
+		return t, nil
+	}
+
+	ok, err := folderExists(ls.s3, t.bucket, t.prefix)
+	if err == nil && !ok && len(t.prefix) > 0 && len(pattern) == 0 {
+		// Not a folder, but the path may point to a file.
+		key := strings.TrimSuffix(t.prefix, context.PathDelimiter)
+		if ok, err = ls.s3.ObjectExists(t.bucket, key); ok {
+			t.prefix = strings.TrimSuffix(t.prefix, path.Base(key)+context.PathDelimiter)
+			t.pattern = ls.escapePattern(path.Base(key))
+		}
+	}
+
+	if err != nil {
+		return lsTarget{}, err
+	} else if !ok {
+		return lsTarget{}, errors.New("No such file or directory: " + displayPath(s3Path))
+	}
+
+	return t, nil
+}
+
+// lsBuckets outputs the name of each bucket matching the pattern, and returns the names that were output.
+func (ls LsCommand) lsBuckets(out Outputter, flags util.Flags, pattern string) ([]string, error) {
+	buckets, err := ls.s3.LsBuckets()
+	if err != nil {
+		return nil, err
+	}
+
+	// Buckets are returned in alphabetical order, so reversing is all the sorting that's possible.
+	if flags.Has(lsFlagReverse) {
+		for i, j := 0, len(buckets)-1; i < j; i, j = i+1, j-1 {
+			buckets[i], buckets[j] = buckets[j], buckets[i]
 		}
+	}
 
-		// Skip the folder itself as it will only be shown as "/" once the prefix is stripped above.
-		if len(f) == 0 || f == context.PathDelimiter {
+	var matched []string
+	for _, b := range buckets {
+		if ok, _ := path.Match(pattern, b); len(pattern) > 0 && !ok {
 			continue
 		}
 
-		// Only display the folder name, if this is an object within a folder.
-		// For example, 'folder/file.txt' becomes 'folder/'.
-		if strings.Contains(f, context.PathDelimiter) {
-			f = fmt.Sprintf("%v%v", strings.Split(f, context.PathDelimiter)[0], context.PathDelimiter)
+		matched = append(matched, b)
+		out.Write("\n" + ls.prefixOutput(b, true))
+	}
+	return matched, nil
+}
+
+// lsObjects outputs the folders and files within a prefix that match the pattern, and returns the full prefix of
+// each folder that was output, in the order they were output.
+//
+// Entries are output as each page is received unless a sort order is requested, in which case the full listing is
+// retrieved and sorted first.
+func (ls LsCommand) lsObjects(out Outputter, flags util.Flags, bucket, prefix, pattern string) ([]string, error) {
+	sorted := flags.Has(lsFlagTime) || flags.Has(lsFlagSize) || flags.Has(lsFlagReverse)
+	var entries []lsEntry
+	var folders []string
+
+	// output writes an entry, and stores its full prefix if it's a folder.
+	output := func(e lsEntry) {
+		out.Write("\n" + ls.entryOutput(e, flags))
+		if e.isFolder {
+			folders = append(folders, prefix+e.obj.Key)
+		}
+	}
+
+	// handle outputs an entry immediately, or stores it to be sorted, if it matches the pattern.
+	handle := func(e lsEntry) {
+		if ok, _ := path.Match(pattern, strings.TrimSuffix(e.obj.Key, context.PathDelimiter)); len(pattern) > 0 && !ok {
+			return
 		}
 
-		// Add this file/folder name to the output text if it's not already.
-		if _, ok := cache[f]; !ok {
-			cache[f] = true
-			out.Write("\n" + ls.prefixOutput(f, isBucketList))
+		if sorted {
+			entries = append(entries, e)
+		} else {
+			output(e)
 		}
 	}
 
-	return nil
+	// Only list keys that begin with the literal portion of the pattern, so that S3 does the bulk of the filtering.
+	// Folder vs. file classification comes from S3 grouping keys by the path delimiter, so only the immediate
+	// contents of the prefix are listed.
+	err := ls.s3.LsDir(bucket, prefix+ls.literalPrefix(pattern), func(dirs []string, files []client.Object) bool {
+		for _, f := range dirs {
+			handle(lsEntry{obj: client.Object{Key: strings.TrimPrefix(f, prefix)}, isFolder: true})
+		}
+
+		for _, f := range files {
+			// Remove the prefix so that only the name relative to the listed folder is output.
+			f.Key = strings.TrimPrefix(f.Key, prefix)
+
+			// Skip the folder itself, which is returned as an empty name once the prefix is stripped above.
+			if len(f.Key) == 0 {
+				continue
+			}
+
+			handle(lsEntry{obj: f})
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if sorted {
+		ls.sortEntries(entries, flags)
+		for _, e := range entries {
+			output(e)
+		}
+	}
+
+	return folders, nil
+}
+
+// splitPattern separates a target into the folder to list and a glob pattern to filter its contents by.
+//
+// Targets without any glob characters are returned as the folder, with an empty pattern. Glob characters are only
+// supported in the final element of the target.
+func (LsCommand) splitPattern(target string) (string, string, error) {
+	if !strings.ContainsAny(target, lsGlobChars) {
+		return target, "", nil
+	}
+
+	dir, pattern := path.Split(target)
+	if strings.ContainsAny(dir, lsGlobChars) {
+		return "", "", fmt.Errorf("Patterns are only supported in the final path element: %v", target)
+	} else if _, err := path.Match(pattern, ""); err != nil {
+		return "", "", fmt.Errorf("Invalid pattern: %v", pattern)
+	}
+
+	return dir, pattern, nil
+}
+
+// literalPrefix returns the portion of a glob pattern that precedes the first glob or escape character.
+func (LsCommand) literalPrefix(pattern string) string {
+	if idx := strings.IndexAny(pattern, lsGlobChars+lsEscapeChar); idx >= 0 {
+		return pattern[:idx]
+	}
+
+	return pattern
+}
+
+// escapePattern returns a glob pattern that matches only the literal name provided.
+func (LsCommand) escapePattern(name string) string {
+	var buf bytes.Buffer
+	for _, c := range name {
+		if strings.ContainsRune(lsGlobChars+lsEscapeChar, c) {
+			buf.WriteString(lsEscapeChar)
+		}
+		buf.WriteRune(c)
+	}
+
+	return buf.String()
+}
+
+// sortEntries sorts entries by name, or by modification time (newest first) or size (largest first) when the
+// corresponding flags are provided, optionally reversing the order.
+func (LsCommand) sortEntries(entries []lsEntry, flags util.Flags) {
+	less := func(a, b lsEntry) bool {
+		return a.obj.Key < b.obj.Key
+	}
+
+	if flags.Has(lsFlagTime) {
+		less = func(a, b lsEntry) bool {
+			if !a.obj.LastModified.Equal(b.obj.LastModified) {
+				return a.obj.LastModified.After(b.obj.LastModified)
+			}
+			return a.obj.Key < b.obj.Key
+		}
+	} else if flags.Has(lsFlagSize) {
+		less = func(a, b lsEntry) bool {
+			if a.obj.Size != b.obj.Size {
+				return a.obj.Size > b.obj.Size
+			}
+			return a.obj.Key < b.obj.Key
+		}
+	}
+
+	var s sort.Interface = lsSorter{entries, less}
+	if flags.Has(lsFlagReverse) {
+		s = sort.Reverse(s)
+	}
+	sort.Sort(s)
+}
+
+// entryOutput returns the output for an entry, based on the format requested by the flags.
+func (ls LsCommand) entryOutput(e lsEntry, flags util.Flags) string {
+	if flags.Has(lsFlagLong) {
+		return ls.longOutput(e.obj, e.isFolder, flags.Has(lsFlagHuman))
+	}
+
+	return ls.prefixOutput(e.obj.Key, false)
 }
 
 // prefixOutput returns a modified version of a bucket/folder/filename by prepending the appropriate prefix.
@@ -93,15 +404,43 @@ func (LsCommand) prefixOutput(out string, isBucketList bool) string {
 	return fmt.Sprintf("%v %v", prefix, out)
 }
 
+// longOutput returns a table row describing an object, including its storage class, owner, size and last-modified
+// time, in the style of 'ls -l' on a local filesystem.
+//
+// Folders have no metadata of their own, so only their name is included.
+func (LsCommand) longOutput(obj client.Object, isFolder, human bool) string {
+	if isFolder {
+		return fmt.Sprintf("%-12v %-16v %10v %16v %v", lsFolderClass, "", "-", "", obj.Key)
+	}
+
+	size := fmt.Sprintf("%d", obj.Size)
+	if human {
+		size = util.HumanSize(obj.Size)
+	}
+
+	return fmt.Sprintf("%-12v %-16v %10v %16v %v", obj.StorageClass, obj.Owner, size, obj.LastModified.Local().Format(lsTimeFormat), obj.Key)
+}
+
 // IsLongRunning returns true because 'ls' requires a network operation.
 func (LsCommand) IsLongRunning() bool {
 	return true
 }
 
 // NewLs initializes and returns an LsCommand.
-func NewLs(s3 S3Client, con *context.Context) LsCommand {
+func NewLs(s3 S3Client, con *context.Context, args []string) LsCommand {
 	return LsCommand{
-		s3:  s3,
-		con: con,
+		s3:   s3,
+		con:  con,
+		args: args,
 	}
 }
+
+// lsSorter implements sort.Interface for a slice of entries, using a provided comparison function.
+type lsSorter struct {
+	entries []lsEntry
+	less    func(a, b lsEntry) bool
+}
+
+func (s lsSorter) Len() int           { return len(s.entries) }
+func (s lsSorter) Swap(i, j int)      { s.entries[i], s.entries[j] = s.entries[j], s.entries[i] }
+func (s lsSorter) Less(i, j int) bool { return s.less(s.entries[i], s.entries[j]) }
@@ -2,9 +2,12 @@ package command
 
 import (
 	"errors"
+	"path"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/KyleBanks/s3fs/client"
 	"github.com/KyleBanks/s3fs/handler/command/context"
 )
 
@@ -22,13 +25,13 @@ func TestLsCommand_Execute(t *testing.T) {
 		s3.lsBucketsCallback = func() ([]string, error) {
 			return samples, nil
 		}
-		s3.lsObjectsCallback = func(a, b string) ([]string, error) {
-			t.Fatalf("LsObjects should not be called when context is at root")
-			return nil, nil
+		s3.lsDirCallback = func(a, b string, fn func([]string, []client.Object) bool) error {
+			t.Fatalf("LsDir should not be called when context is at root")
+			return nil
 		}
 
 		// Execute the command.
-		ls := NewLs(&s3, &con)
+		ls := NewLs(&s3, &con, nil)
 		if err := ls.Execute(&out); err != nil {
 			t.Fatal(err)
 		}
@@ -58,13 +61,13 @@ func TestLsCommand_Execute(t *testing.T) {
 		s3.lsBucketsCallback = func() ([]string, error) {
 			return nil, mockErr
 		}
-		s3.lsObjectsCallback = func(a, b string) ([]string, error) {
-			t.Fatalf("LsObjects should not be called when context is at root")
-			return nil, nil
+		s3.lsDirCallback = func(a, b string, fn func([]string, []client.Object) bool) error {
+			t.Fatalf("LsDir should not be called when context is at root")
+			return nil
 		}
 
 		// Execute the command and validate the error is bubbled up.
-		ls := NewLs(&s3, &con)
+		ls := NewLs(&s3, &con, nil)
 		if err := ls.Execute(&out); err != mockErr {
 			t.Fatalf("Expected error to be passed up the stack: %v", err)
 		}
@@ -105,22 +108,27 @@ func TestLsCommand_Execute(t *testing.T) {
 
 			// Define the sample output.
 			samples := []string{samplePrefix + "subfolder/", samplePrefix + "index.html"}
+			folders := samples[:1]
+			files := []client.Object{{Key: samples[1]}}
 
 			// Override ls functions.
 			s3.lsBucketsCallback = func() ([]string, error) {
 				t.Fatalf("LsBuckets should not be called when context is not at root")
 				return nil, nil
 			}
-			s3.lsObjectsCallback = func(bucket, prefix string) ([]string, error) {
+			s3.lsDirCallback = func(bucket, prefix string, fn func([]string, []client.Object) bool) error {
 				if bucket != sampleBucket || prefix != samplePrefix {
-					t.Fatalf("Unexpected bucket/prefix provided to LsObjects: {Bucket: %v, ExpectedBucket: %v, Prefix: %v, ExpectedPrefix: %v}", bucket, sampleBucket, prefix, samplePrefix)
+					t.Fatalf("Unexpected bucket/prefix provided to LsDir: {Bucket: %v, ExpectedBucket: %v, Prefix: %v, ExpectedPrefix: %v}", bucket, sampleBucket, prefix, samplePrefix)
 				}
 
-				return samples, nil
+				// Deliver the folders and files as separate pages, including the folder marker itself.
+				fn(folders, []client.Object{{Key: samplePrefix}})
+				fn(nil, files)
+				return nil
 			}
 
 			// Execute the command.
-			ls := NewLs(&s3, &con)
+			ls := NewLs(&s3, &con, nil)
 			if err := ls.Execute(&out); err != nil {
 				t.Fatal(err)
 			}
@@ -152,11 +160,11 @@ func TestLsCommand_Execute(t *testing.T) {
 			t.Fatalf("LsBuckets should not be called when context is not at root")
 			return nil, nil
 		}
-		s3.lsObjectsCallback = func(bucket, prefix string) ([]string, error) {
-			return nil, mockErr
+		s3.lsDirCallback = func(bucket, prefix string, fn func([]string, []client.Object) bool) error {
+			return mockErr
 		}
 
-		ls := NewLs(&s3, &con)
+		ls := NewLs(&s3, &con, nil)
 		if err := ls.Execute(&out); err != mockErr {
 			t.Fatalf("Expected error to be passed up the stack: %v", err)
 		}
@@ -167,10 +175,417 @@ func TestLsCommand_Execute(t *testing.T) {
 	}
 }
 
+func TestLsCommand_Execute_long(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	con.UpdatePath("bucket/folder")
+
+	modified := time.Date(2016, 10, 13, 20, 17, 0, 0, time.Local)
+	s3.lsDirCallback = func(bucket, prefix string, fn func([]string, []client.Object) bool) error {
+		fn([]string{"folder/sub/"}, []client.Object{
+			{Key: "folder/file.txt", Size: 2048, StorageClass: "STANDARD", Owner: "owner", LastModified: modified},
+		})
+		return nil
+	}
+
+	// Long listing
+	{
+		var out mockOutputter
+
+		ls := NewLs(&s3, &con, []string{"-l"})
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(out.output) != 2 {
+			t.Fatalf("Unexpected output length for long LS: %v", out.output)
+		} else if !strings.Contains(out.output[0], lsFolderClass) || !strings.HasSuffix(out.output[0], " sub/") {
+			t.Fatalf("Unexpected folder output for long LS: %v", out.output[0])
+		}
+
+		for _, expected := range []string{"STANDARD", "owner", "2048", modified.Format(lsTimeFormat), " file.txt"} {
+			if !strings.Contains(out.output[1], expected) {
+				t.Fatalf("Expected file output to contain '%v': %v", expected, out.output[1])
+			}
+		}
+	}
+
+	// Human-readable sizes
+	{
+		var out mockOutputter
+
+		ls := NewLs(&s3, &con, []string{"-lh"})
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(out.output) != 2 || !strings.Contains(out.output[1], " 2.0K ") {
+			t.Fatalf("Expected human-readable size in output: %v", out.output)
+		}
+	}
+}
+
+func TestLsCommand_Execute_sorted(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	con.UpdatePath("bucket")
+
+	now := time.Now()
+	s3.lsDirCallback = func(bucket, prefix string, fn func([]string, []client.Object) bool) error {
+		fn(nil, []client.Object{
+			{Key: "a.txt", Size: 2, LastModified: now.Add(-time.Hour)},
+			{Key: "b.txt", Size: 3, LastModified: now.Add(-time.Minute)},
+		})
+		fn(nil, []client.Object{
+			{Key: "c.txt", Size: 1, LastModified: now},
+		})
+		return nil
+	}
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"-t"}, []string{"c.txt", "b.txt", "a.txt"}},
+		{[]string{"-S"}, []string{"b.txt", "a.txt", "c.txt"}},
+		{[]string{"-r"}, []string{"c.txt", "b.txt", "a.txt"}},
+		{[]string{"-tr"}, []string{"a.txt", "b.txt", "c.txt"}},
+		{[]string{"-S", "-r"}, []string{"c.txt", "a.txt", "b.txt"}},
+	}
+
+	for _, test := range tests {
+		var out mockOutputter
+
+		ls := NewLs(&s3, &con, test.args)
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(out.output) != len(test.expected) {
+			t.Fatalf("Unexpected output length for %v: %v", test.args, out.output)
+		}
+		for i, expected := range test.expected {
+			if !strings.HasSuffix(out.output[i], expected) {
+				t.Fatalf("Unexpected order for %v: {Expected: %v, Actual: %v}", test.args, test.expected, out.output)
+			}
+		}
+	}
+}
+
+func TestLsCommand_Execute_pattern(t *testing.T) {
+	// Object pattern
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.pathExistsCallback = func(bucket, prefix string) (bool, error) {
+			return bucket == "bucket" && prefix == "logs/", nil
+		}
+		s3.lsDirCallback = func(bucket, prefix string, fn func([]string, []client.Object) bool) error {
+			if bucket != "bucket" || prefix != "logs/2024-" {
+				t.Fatalf("Unexpected bucket/prefix provided to LsDir: %v, %v", bucket, prefix)
+			}
+
+			fn([]string{"logs/2024-01/"}, []client.Object{{Key: "logs/2024-01.csv"}, {Key: "logs/2024-01.txt"}})
+			return nil
+		}
+
+		ls := NewLs(&s3, &con, []string{"logs/2024-*.csv"})
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(out.output) != 1 || !strings.HasSuffix(out.output[0], " 2024-01.csv") {
+			t.Fatalf("Unexpected output for pattern: %v", out.output)
+		}
+	}
+
+	// Bucket pattern
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+
+		s3.lsBucketsCallback = func() ([]string, error) {
+			return []string{"logs-1", "data", "logs-2"}, nil
+		}
+
+		ls := NewLs(&s3, &con, []string{"logs-*"})
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(out.output) != 2 || !strings.HasSuffix(out.output[0], "logs-1") || !strings.HasSuffix(out.output[1], "logs-2") {
+			t.Fatalf("Unexpected output for bucket pattern: %v", out.output)
+		}
+	}
+
+	// Invalid patterns
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		for _, pattern := range []string{"logs-*/file.txt", "logs-[", "bucket/[a-"} {
+			ls := NewLs(&s3, &con, []string{pattern})
+			if err := ls.Execute(&out); err == nil {
+				t.Fatalf("Expected error for invalid pattern: %v", pattern)
+			}
+		}
+	}
+}
+
+func TestLsCommand_Execute_paths(t *testing.T) {
+	// mockS3 returns a client containing 'bucket/folder/file.txt'.
+	mockS3 := func() mockS3Client {
+		var s3 mockS3Client
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return bucket == "bucket", nil
+		}
+		s3.pathExistsCallback = func(bucket, prefix string) (bool, error) {
+			return bucket == "bucket" && prefix == "folder/", nil
+		}
+		s3.objectExistsCallback = func(bucket, key string) (bool, error) {
+			return bucket == "bucket" && key == "folder/file.txt", nil
+		}
+		s3.lsDirCallback = func(bucket, prefix string, fn func([]string, []client.Object) bool) error {
+			fn(nil, []client.Object{{Key: "folder/file.txt"}, {Key: "folder/file.txt.bak"}})
+			return nil
+		}
+		return s3
+	}
+
+	// Folder
+	{
+		s3 := mockS3()
+		var con context.Context
+		var out mockOutputter
+
+		s3.lsDirCallback = func(bucket, prefix string, fn func([]string, []client.Object) bool) error {
+			if bucket != "bucket" || prefix != "folder/" {
+				t.Fatalf("Unexpected bucket/prefix provided to LsDir: %v, %v", bucket, prefix)
+			}
+			fn(nil, []client.Object{{Key: "folder/file.txt"}})
+			return nil
+		}
+
+		ls := NewLs(&s3, &con, []string{"bucket/folder"})
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		} else if len(out.output) != 1 || !strings.HasSuffix(out.output[0], " file.txt") {
+			t.Fatalf("Unexpected output for folder: %v", out.output)
+		}
+	}
+
+	// File
+	{
+		s3 := mockS3()
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		ls := NewLs(&s3, &con, []string{"folder/file.txt"})
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		} else if len(out.output) != 1 || !strings.HasSuffix(out.output[0], " file.txt") {
+			t.Fatalf("Unexpected output for file: %v", out.output)
+		}
+	}
+
+	// Multiple paths
+	{
+		s3 := mockS3()
+		var con context.Context
+		var out mockOutputter
+
+		s3.lsBucketsCallback = func() ([]string, error) {
+			return []string{"bucket"}, nil
+		}
+
+		ls := NewLs(&s3, &con, []string{"/", "bucket/folder/"})
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		output := strings.Join(out.output, "")
+		if !strings.Contains(output, "\n/:\n[B] bucket") || !strings.Contains(output, "\n\nbucket/folder/:\n file.txt") {
+			t.Fatalf("Unexpected output for multiple paths: %v", output)
+		}
+	}
+
+	// Non-existent paths
+	{
+		for _, arg := range []string{"fake", "bucket/fake", "bucket/folder/fake.txt"} {
+			s3 := mockS3()
+			var con context.Context
+			var out mockOutputter
+
+			ls := NewLs(&s3, &con, []string{"bucket", arg})
+			if err := ls.Execute(&out); err == nil {
+				t.Fatalf("Expected error for non-existent path: %v", arg)
+			} else if len(out.output) != 0 {
+				t.Fatalf("Expected no output when a path doesn't exist: %v", out.output)
+			}
+		}
+	}
+
+	// S3 error
+	{
+		s3 := mockS3()
+		var con context.Context
+		var out mockOutputter
+		mockErr := errors.New("Mock Error")
+
+		s3.pathExistsCallback = func(bucket, prefix string) (bool, error) {
+			return false, mockErr
+		}
+
+		ls := NewLs(&s3, &con, []string{"bucket/folder"})
+		if err := ls.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestLsCommand_Execute_recursive(t *testing.T) {
+	objects := []client.Object{
+		{Key: "folder/a.txt"},
+		{Key: "folder/sub/b.txt"},
+		{Key: "folder/sub/deeper/c.txt"},
+		{Key: "folder/sub2/d.txt"},
+	}
+
+	// Folder
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket/folder")
+		s3.lsDirCallback = mockLsDir(objects)
+
+		ls := NewLs(&s3, &con, []string{"-R"})
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := strings.Join([]string{
+			"\n.:", "\n sub/", "\n sub2/", "\n a.txt",
+			"\n\n./sub:", "\n deeper/", "\n b.txt",
+			"\n\n./sub/deeper:", "\n c.txt",
+			"\n\n./sub2:", "\n d.txt",
+		}, "")
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected recursive output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+
+	// Root
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		s3.lsBucketsCallback = func() ([]string, error) {
+			return []string{"bucket"}, nil
+		}
+		s3.lsDirCallback = mockLsDir(objects[:1])
+
+		ls := NewLs(&s3, &con, []string{"-R"})
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := strings.Join([]string{
+			"\n.:", "\n[B] bucket",
+			"\n\n./bucket:", "\n folder/",
+			"\n\n./bucket/folder:", "\n a.txt",
+		}, "")
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected recursive output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+
+	// S3 error
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		mockErr := errors.New("Mock Error")
+
+		s3.lsDirCallback = func(bucket, prefix string, fn func([]string, []client.Object) bool) error {
+			if len(prefix) > 0 {
+				return mockErr
+			}
+			return mockLsDir(objects)(bucket, prefix, fn)
+		}
+
+		ls := NewLs(&s3, &con, []string{"-R"})
+		if err := ls.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestLsCommand_Execute_emptyFolder(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {
+			{Key: "empty/"},
+			{Key: "folder/a.txt"},
+		},
+	}
+
+	// The marker object is listed as a folder in its parent.
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		ls := NewLs(&s3, &con, nil)
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "\n empty/\n folder/"
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+
+	// The empty folder can be listed, and contains nothing.
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		ls := NewLs(&s3, &con, []string{"empty"})
+		if err := ls.Execute(&out); err != nil {
+			t.Fatal(err)
+		} else if len(out.output) != 0 {
+			t.Fatalf("Unexpected output for empty folder: %q", out.output)
+		}
+	}
+}
+
+func TestLsCommand_escapePattern(t *testing.T) {
+	var ls LsCommand
+
+	for _, name := range []string{"file.txt", "file[1].txt", "*?.txt", "back\\slash"} {
+		pattern := ls.escapePattern(name)
+		if ok, err := path.Match(pattern, name); err != nil || !ok {
+			t.Fatalf("Expected escaped pattern '%v' to match '%v': %v", pattern, name, err)
+		}
+	}
+}
+
 func TestLsCommand_prefixOutput(t *testing.T) {
 	var s3 mockS3Client
 	var con context.Context
-	ls := NewLs(&s3, &con)
+	ls := NewLs(&s3, &con, nil)
 
 	// Bucket
 	{
@@ -204,7 +619,7 @@ func TestLsCommand_IsLongRunning(t *testing.T) {
 	var s3 mockS3Client
 	var con context.Context
 
-	ls := NewLs(&s3, &con)
+	ls := NewLs(&s3, &con, nil)
 	if !ls.IsLongRunning() {
 		t.Fatalf("Expected LsCommand to always be long running")
 	}
@@ -214,10 +629,14 @@ func TestNewLs(t *testing.T) {
 	var s3 mockS3Client
 	var con context.Context
 
-	ls := NewLs(&s3, &con)
+	args := []string{"-l"}
+
+	ls := NewLs(&s3, &con, args)
 	if ls.s3 != &s3 {
 		t.Fatalf("Unexpected S3 client stored on ls command: %v", ls.s3)
 	} else if ls.con != &con {
 		t.Fatalf("Unexpected Context stored on ls command: %v", ls.con)
+	} else if ls.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on ls command: %v", ls.args)
 	}
 }
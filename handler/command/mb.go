@@ -0,0 +1,58 @@
+package command
+
+import (
+	"errors"
+
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// mbFlagRegion indicates the region to create the bucket in.
+	mbFlagRegion = "region"
+)
+
+// MbCommand makes a new bucket.
+type MbCommand struct {
+	s3 S3Client
+
+	args []string
+}
+
+// Execute performs an 'mb' command by creating the named bucket, in the region provided or the default region.
+func (mb MbCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(mb.args, mbFlagRegion)
+	if len(flags.Args) == 0 {
+		return errors.New("Missing bucket name.")
+	}
+
+	name, err := bucketName(flags.Args[0])
+	if err != nil {
+		return err
+	}
+
+	if ok, err := mb.s3.BucketExists(name); err != nil {
+		return err
+	} else if ok {
+		return errors.New("Bucket already exists: " + displayPath([]string{name}))
+	}
+
+	if err := mb.s3.CreateBucket(name, flags.Value(mbFlagRegion)); err != nil {
+		return err
+	}
+
+	out.Write("\nCreated bucket: " + displayPath([]string{name}))
+	return nil
+}
+
+// IsLongRunning returns true because 'mb' requires network operations.
+func (MbCommand) IsLongRunning() bool {
+	return true
+}
+
+// NewMb initializes and returns an MbCommand.
+func NewMb(s3 S3Client, args []string) MbCommand {
+	return MbCommand{
+		s3:   s3,
+		args: args,
+	}
+}
@@ -0,0 +1,113 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMbCommand_Execute(t *testing.T) {
+	tests := []struct {
+		args   []string
+		name   string
+		region string
+	}{
+		{[]string{"bucket"}, "bucket", ""},
+		{[]string{"/bucket/"}, "bucket", ""},
+		{[]string{"bucket", "--region", "eu-west-1"}, "bucket", "eu-west-1"},
+		{[]string{"--region=eu-west-1", "bucket"}, "bucket", "eu-west-1"},
+	}
+
+	for _, test := range tests {
+		var s3 mockS3Client
+		var out mockOutputter
+
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return false, nil
+		}
+
+		var created bool
+		s3.createBucketCallback = func(bucket, region string) error {
+			if bucket != test.name || region != test.region {
+				t.Fatalf("Unexpected bucket created for %v: {Expected: %v %v, Actual: %v %v}", test.args, test.name, test.region, bucket, region)
+			}
+			created = true
+			return nil
+		}
+
+		mb := NewMb(&s3, test.args)
+		if err := mb.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if !created {
+			t.Fatalf("Expected bucket to be created for %v", test.args)
+		} else if len(out.output) != 1 || !strings.Contains(out.output[0], "Created bucket: /"+test.name) {
+			t.Fatalf("Unexpected output: %v", out.output)
+		}
+	}
+}
+
+func TestMbCommand_Execute_invalid(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"/"},
+		{"bucket/folder"},
+		{"existing"},
+	} {
+		var s3 mockS3Client
+		var out mockOutputter
+
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return bucket == "existing", nil
+		}
+		s3.createBucketCallback = func(bucket, region string) error {
+			t.Fatalf("CreateBucket should not be called for invalid args: %v", args)
+			return nil
+		}
+
+		mb := NewMb(&s3, args)
+		if err := mb.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+
+	// S3 error
+	{
+		var s3 mockS3Client
+		var out mockOutputter
+		mockErr := errors.New("Mock Error")
+
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return false, nil
+		}
+		s3.createBucketCallback = func(bucket, region string) error {
+			return mockErr
+		}
+
+		mb := NewMb(&s3, []string{"bucket"})
+		if err := mb.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestMbCommand_IsLongRunning(t *testing.T) {
+	mb := NewMb(nil, nil)
+
+	if !mb.IsLongRunning() {
+		t.Fatal("Expected MbCommand to always be long running")
+	}
+}
+
+func TestNewMb(t *testing.T) {
+	var s3 mockS3Client
+	args := []string{"bucket"}
+
+	mb := NewMb(&s3, args)
+	if mb.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on mb command: %v", mb.s3)
+	} else if mb.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on mb command: %v", mb.args)
+	}
+}
@@ -0,0 +1,96 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// mkdirFlagParents indicates that missing parent folders should be created, and that existing folders are
+	// not an error.
+	mkdirFlagParents = "p"
+)
+
+// MkdirCommand creates empty folders.
+type MkdirCommand struct {
+	s3  S3Client
+	con *context.Context
+
+	args []string
+}
+
+// Execute performs a 'mkdir' command by writing a zero-byte folder marker object for each target folder.
+//
+// Without the parents flag, the parent of each target must already exist and the target itself must not.
+func (mkdir MkdirCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(mkdir.args)
+	if len(flags.Args) == 0 {
+		return errors.New("Missing target folder.")
+	}
+
+	for _, arg := range flags.Args {
+		targetPath := mkdir.con.CalculatePath(arg)
+		if len(targetPath) < 2 {
+			return fmt.Errorf("Cannot create a bucket with mkdir, use mb instead: %v", displayPath(targetPath))
+		}
+
+		// Validate the bucket once, so that each missing folder can be checked by prefix alone.
+		if ok, err := mkdir.s3.BucketExists(targetPath[0]); err != nil {
+			return err
+		} else if !ok {
+			return fmt.Errorf("No such bucket: %v", displayPath(targetPath[:1]))
+		}
+
+		// Determine the first folder that needs to be created, in order of depth.
+		first := len(targetPath)
+		if flags.Has(mkdirFlagParents) {
+			first = 2
+		} else {
+			parentPath := targetPath[:len(targetPath)-1]
+			bucket, prefix := splitFolderPath(parentPath)
+			if ok, err := folderExists(mkdir.s3, bucket, prefix); err != nil {
+				return err
+			} else if !ok {
+				return fmt.Errorf("No such file or directory: %v", displayPath(parentPath)+context.PathDelimiter)
+			}
+		}
+
+		for i := first; i <= len(targetPath); i++ {
+			bucket, prefix := splitFolderPath(targetPath[:i])
+			if ok, err := mkdir.s3.PathExists(bucket, prefix); err != nil {
+				return err
+			} else if ok {
+				if flags.Has(mkdirFlagParents) {
+					continue
+				}
+
+				return fmt.Errorf("Folder already exists: %v", displayPath(targetPath)+context.PathDelimiter)
+			}
+
+			if err := mkdir.s3.CreateFolder(bucket, prefix); err != nil {
+				return err
+			}
+
+			out.Write("\nCreated: " + displayPath(targetPath[:i]) + context.PathDelimiter)
+		}
+	}
+
+	return nil
+}
+
+// IsLongRunning returns true because 'mkdir' requires network operations.
+func (MkdirCommand) IsLongRunning() bool {
+	return true
+}
+
+// NewMkdir initializes and returns a MkdirCommand.
+func NewMkdir(s3 S3Client, con *context.Context, args []string) MkdirCommand {
+	return MkdirCommand{
+		s3:   s3,
+		con:  con,
+		args: args,
+	}
+}
@@ -0,0 +1,135 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+func TestMkdirCommand_Execute(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {
+			{Key: "a.txt"},
+			{Key: "empty/"},
+			{Key: "folder/b.txt"},
+		},
+	}
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"new"}, []string{"bucket/new/"}},
+		{[]string{"new/"}, []string{"bucket/new/"}},
+		{[]string{"folder/new", "empty/new"}, []string{"bucket/folder/new/", "bucket/empty/new/"}},
+		{[]string{"/bucket/new"}, []string{"bucket/new/"}},
+		{[]string{"-p", "a/b/c"}, []string{"bucket/a/", "bucket/a/b/", "bucket/a/b/c/"}},
+		{[]string{"-p", "folder/sub/new"}, []string{"bucket/folder/sub/", "bucket/folder/sub/new/"}},
+		{[]string{"-p", "folder", "empty"}, nil},
+	}
+
+	for _, test := range tests {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		var created []string
+		s3.createFolderCallback = func(bucket, prefix string) error {
+			created = append(created, bucket+"/"+prefix)
+			return nil
+		}
+
+		mkdir := NewMkdir(&s3, &con, test.args)
+		if err := mkdir.Execute(&out); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.args, err)
+		}
+
+		if strings.Join(created, ",") != strings.Join(test.expected, ",") {
+			t.Fatalf("Unexpected folders created for %v: {Expected: %v, Actual: %v}", test.args, test.expected, created)
+		} else if len(out.output) != len(test.expected) {
+			t.Fatalf("Unexpected output for %v: %v", test.args, out.output)
+		}
+
+		for i, o := range out.output {
+			if !strings.Contains(o, "Created: /"+test.expected[i]) {
+				t.Fatalf("Unexpected output for %v: {Expected: %v, Actual: %v}", test.args, test.expected[i], o)
+			}
+		}
+	}
+}
+
+func TestMkdirCommand_Execute_invalid(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "empty/"}, {Key: "folder/b.txt"}},
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"/"},
+		{"/newbucket"},
+		{"/fake/folder"},
+		{"missing/new"},
+		{"folder"},
+		{"empty/"},
+	} {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.createFolderCallback = func(bucket, prefix string) error {
+			t.Fatalf("CreateFolder should not be called for invalid args: %v", args)
+			return nil
+		}
+
+		mkdir := NewMkdir(&s3, &con, args)
+		if err := mkdir.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+
+	// S3 error
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		mockErr := errors.New("Mock Error")
+
+		s3.createFolderCallback = func(bucket, prefix string) error {
+			return mockErr
+		}
+
+		mkdir := NewMkdir(&s3, &con, []string{"new"})
+		if err := mkdir.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestMkdirCommand_IsLongRunning(t *testing.T) {
+	mkdir := NewMkdir(nil, nil, nil)
+
+	if !mkdir.IsLongRunning() {
+		t.Fatal("Expected MkdirCommand to always be long running")
+	}
+}
+
+func TestNewMkdir(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	args := []string{"folder"}
+
+	mkdir := NewMkdir(&s3, &con, args)
+	if mkdir.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on mkdir command: %v", mkdir.s3)
+	} else if mkdir.con != &con {
+		t.Fatalf("Unexpected Context stored on mkdir command: %v", mkdir.con)
+	} else if mkdir.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on mkdir command: %v", mkdir.args)
+	}
+}
@@ -0,0 +1,108 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// mvArgsIndexSource indicates the expected argument index for the source to move.
+	mvArgsIndexSource = 0
+
+	// mvArgsIndexDestination indicates the expected argument index for the move destination.
+	mvArgsIndexDestination = 1
+)
+
+// MvCommand moves or renames objects and folders, within or across buckets.
+type MvCommand struct {
+	s3  S3Client
+	con *context.Context
+
+	args []string
+}
+
+// Execute performs a 'mv' command by copying the source object or folder to the destination server-side, and
+// then deleting the originals.
+//
+// When moving a folder, every object is copied before any are deleted, and only objects that were successfully
+// copied are deleted, so that a partial failure never loses data.
+func (mv MvCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(mv.args)
+	if len(flags.Args) < mvArgsIndexDestination+1 {
+		return errors.New("Missing source or destination.")
+	}
+
+	job, err := resolveCopy(mv.s3, mv.con, flags.Args[mvArgsIndexSource], flags.Args[mvArgsIndexDestination], true)
+	if err != nil {
+		return err
+	}
+
+	srcPath := displayPath([]string{job.srcBucket, job.src})
+	dstPath := displayPath([]string{job.dstBucket, job.dst})
+
+	// Single objects are simply copied and deleted.
+	if !job.isFolder {
+		if err := mv.s3.CopyObject(job.srcBucket, job.src, job.dstBucket, job.dst); err != nil {
+			return err
+		} else if err := mv.s3.DeleteObject(job.srcBucket, job.src); err != nil {
+			return err
+		}
+
+		out.Write(fmt.Sprintf("\nMoved: %v -> %v", srcPath, dstPath))
+		return nil
+	}
+
+	// Copy every object in the folder, recording those that succeeded.
+	var copied []string
+	var copyFailures int
+	err = job.each(mv.s3, func(src, dst string) bool {
+		if err := mv.s3.CopyObject(job.srcBucket, src, job.dstBucket, dst); err != nil {
+			copyFailures++
+			out.Write(fmt.Sprintf("\nFailed to copy: %v: %v", displayPath([]string{job.srcBucket, src}), err))
+			return true
+		}
+
+		copied = append(copied, src)
+		out.Write(fmt.Sprintf("\nCopied: %v -> %v", displayPath([]string{job.srcBucket, src}), displayPath([]string{job.dstBucket, dst})))
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	// Delete only the originals that were copied.
+	deleteFailures, err := mv.s3.DeleteObjects(job.srcBucket, copied)
+	if err != nil {
+		return err
+	}
+	for _, f := range deleteFailures {
+		out.Write("\nFailed to remove original: " + f.Error())
+	}
+
+	out.Write(fmt.Sprintf("\nMoved %d object(s): %v -> %v", len(copied)-len(deleteFailures), srcPath, dstPath))
+
+	if copyFailures > 0 {
+		return fmt.Errorf("Failed to copy %d object(s), the originals have been kept", copyFailures)
+	} else if len(deleteFailures) > 0 {
+		return fmt.Errorf("Failed to remove %d original object(s) after copying", len(deleteFailures))
+	}
+
+	return nil
+}
+
+// IsLongRunning returns true because 'mv' requires network operations.
+func (MvCommand) IsLongRunning() bool {
+	return true
+}
+
+// NewMv initializes and returns an MvCommand.
+func NewMv(s3 S3Client, con *context.Context, args []string) MvCommand {
+	return MvCommand{
+		s3:   s3,
+		con:  con,
+		args: args,
+	}
+}
@@ -0,0 +1,239 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+func TestMvCommand_Execute(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {
+			{Key: "a.txt"},
+			{Key: "folder/b.txt"},
+			{Key: "folder/sub/c.txt"},
+			{Key: "other/d.txt"},
+		},
+		"bucket2": {
+			{Key: "existing/e.txt"},
+		},
+	}
+
+	// Single object
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		var copied, deleted string
+		s3.copyObjectCallback = func(srcBucket, srcKey, dstBucket, dstKey string) error {
+			copied = srcBucket + "/" + srcKey + " -> " + dstBucket + "/" + dstKey
+			return nil
+		}
+		s3.deleteObjectCallback = func(bucket, key string) error {
+			if copied == "" {
+				t.Fatal("Expected object to be copied before it is deleted")
+			}
+			deleted = bucket + "/" + key
+			return nil
+		}
+
+		mv := NewMv(&s3, &con, []string{"a.txt", "/bucket2/renamed.txt"})
+		if err := mv.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if copied != "bucket/a.txt -> bucket2/renamed.txt" {
+			t.Fatalf("Unexpected copy: %v", copied)
+		} else if deleted != "bucket/a.txt" {
+			t.Fatalf("Unexpected delete: %v", deleted)
+		} else if len(out.output) != 1 || !strings.Contains(out.output[0], "Moved: /bucket/a.txt -> /bucket2/renamed.txt") {
+			t.Fatalf("Unexpected output: %v", out.output)
+		}
+	}
+
+	// Folder rename
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		var copied []string
+		s3.copyObjectCallback = func(srcBucket, srcKey, dstBucket, dstKey string) error {
+			copied = append(copied, srcBucket+"/"+srcKey+" -> "+dstBucket+"/"+dstKey)
+			return nil
+		}
+		var deleted []string
+		s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+			if bucket != "bucket" {
+				t.Fatalf("Unexpected bucket: %v", bucket)
+			}
+			deleted = append(deleted, keys...)
+			return nil, nil
+		}
+
+		mv := NewMv(&s3, &con, []string{"folder", "renamed"})
+		if err := mv.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		expectedCopies := "bucket/folder/b.txt -> bucket/renamed/b.txt,bucket/folder/sub/c.txt -> bucket/renamed/sub/c.txt"
+		if strings.Join(copied, ",") != expectedCopies {
+			t.Fatalf("Unexpected copies: {Expected: %v, Actual: %v}", expectedCopies, copied)
+		} else if strings.Join(deleted, ",") != "folder/b.txt,folder/sub/c.txt" {
+			t.Fatalf("Unexpected deletes: %v", deleted)
+		}
+
+		expectedOutput := "\nCopied: /bucket/folder/b.txt -> /bucket/renamed/b.txt" +
+			"\nCopied: /bucket/folder/sub/c.txt -> /bucket/renamed/sub/c.txt" +
+			"\nMoved 2 object(s): /bucket/folder/ -> /bucket/renamed/"
+		if output := strings.Join(out.output, ""); output != expectedOutput {
+			t.Fatalf("Unexpected output: {Expected: %q, Actual: %q}", expectedOutput, output)
+		}
+	}
+}
+
+func TestMvCommand_Execute_partialFailure(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {
+			{Key: "folder/a.txt"},
+			{Key: "folder/b.txt"},
+			{Key: "folder/c.txt"},
+		},
+	}
+
+	// Failed copies are kept
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.copyObjectCallback = func(srcBucket, srcKey, dstBucket, dstKey string) error {
+			if srcKey == "folder/b.txt" {
+				return errors.New("Mock Error")
+			}
+			return nil
+		}
+		var deleted []string
+		s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+			deleted = append(deleted, keys...)
+			return nil, nil
+		}
+
+		mv := NewMv(&s3, &con, []string{"folder", "renamed"})
+		if err := mv.Execute(&out); err == nil {
+			t.Fatal("Expected error when a copy fails")
+		}
+
+		if strings.Join(deleted, ",") != "folder/a.txt,folder/c.txt" {
+			t.Fatalf("Expected only copied objects to be deleted: %v", deleted)
+		} else if !strings.Contains(strings.Join(out.output, ""), "Failed to copy: /bucket/folder/b.txt") {
+			t.Fatalf("Expected failed copy to be output: %v", out.output)
+		}
+	}
+
+	// Failed deletes are reported
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.copyObjectCallback = func(srcBucket, srcKey, dstBucket, dstKey string) error {
+			return nil
+		}
+		s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+			return []client.DeleteError{{Key: "folder/c.txt", Code: "AccessDenied", Message: "Access Denied"}}, nil
+		}
+
+		mv := NewMv(&s3, &con, []string{"folder", "renamed"})
+		if err := mv.Execute(&out); err == nil {
+			t.Fatal("Expected error when a delete fails")
+		}
+
+		if !strings.Contains(strings.Join(out.output, ""), "Failed to remove original: folder/c.txt") {
+			t.Fatalf("Expected failed delete to be output: %v", out.output)
+		}
+	}
+
+	// Copy error on a single object leaves the original
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		mockErr := errors.New("Mock Error")
+
+		s3.copyObjectCallback = func(srcBucket, srcKey, dstBucket, dstKey string) error {
+			return mockErr
+		}
+		s3.deleteObjectCallback = func(bucket, key string) error {
+			t.Fatal("DeleteObject should not be called when the copy fails")
+			return nil
+		}
+
+		mv := NewMv(&s3, &con, []string{"folder/a.txt", "a.txt"})
+		if err := mv.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestMvCommand_Execute_invalid(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "a.txt"}, {Key: "folder/b.txt"}},
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"a.txt"},
+		{"/", "bucket"},
+		{"fake.txt", "b.txt"},
+		{"folder", "folder/sub"},
+		{"a.txt", "a.txt"},
+	} {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.copyObjectCallback = func(srcBucket, srcKey, dstBucket, dstKey string) error {
+			t.Fatalf("CopyObject should not be called for invalid args: %v", args)
+			return nil
+		}
+
+		mv := NewMv(&s3, &con, args)
+		if err := mv.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+}
+
+func TestMvCommand_IsLongRunning(t *testing.T) {
+	mv := NewMv(nil, nil, nil)
+
+	if !mv.IsLongRunning() {
+		t.Fatal("Expected MvCommand to always be long running")
+	}
+}
+
+func TestNewMv(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	args := []string{"src", "dst"}
+
+	mv := NewMv(&s3, &con, args)
+	if mv.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on mv command: %v", mv.s3)
+	} else if mv.con != &con {
+		t.Fatalf("Unexpected Context stored on mv command: %v", mv.con)
+	} else if mv.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on mv command: %v", mv.args)
+	}
+}
@@ -0,0 +1,109 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+// splitFolderPath separates a path calculated by a context into its bucket and folder prefix.
+//
+// The prefix is empty for the root of a bucket, and otherwise always ends with the path delimiter.
+func splitFolderPath(p []string) (bucket, prefix string) {
+	if len(p) == 0 {
+		return "", ""
+	}
+
+	if len(p) > 1 {
+		prefix = strings.Join(p[1:], context.PathDelimiter) + context.PathDelimiter
+	}
+
+	return p[0], prefix
+}
+
+// splitKeyPath separates a path calculated by a context into its bucket and object key.
+func splitKeyPath(p []string) (bucket, key string) {
+	if len(p) == 0 {
+		return "", ""
+	}
+
+	return p[0], strings.Join(p[1:], context.PathDelimiter)
+}
+
+// folderExists returns a bool indicating if the folder represented by a bucket and prefix exists, where an empty
+// prefix represents the bucket itself.
+func folderExists(s3 S3Client, bucket, prefix string) (bool, error) {
+	if len(prefix) == 0 {
+		return s3.BucketExists(bucket)
+	}
+
+	return s3.PathExists(bucket, prefix)
+}
+
+// resolveFile calculates the path of an object argument, relative to the context, and validates that the object
+// exists.
+func resolveFile(s3 S3Client, con *context.Context, arg string) ([]string, error) {
+	p := con.CalculatePath(arg)
+	bucket, key := splitKeyPath(p)
+	if len(key) == 0 {
+		return nil, fmt.Errorf("Target is not a file: %v", displayPath(p))
+	}
+
+	if ok, err := s3.ObjectExists(bucket, key); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.New("No such file or directory: " + displayPath(p))
+	}
+
+	return p, nil
+}
+
+// headFile calculates the path of an object argument, relative to the context, and returns it along with the
+// metadata of the object, which also validates that the object exists.
+func headFile(s3 S3Client, con *context.Context, arg string) ([]string, client.ObjectInfo, error) {
+	p := con.CalculatePath(arg)
+	bucket, key := splitKeyPath(p)
+	if len(key) == 0 {
+		return nil, client.ObjectInfo{}, fmt.Errorf("Target is not a file: %v", displayPath(p))
+	}
+
+	info, err := s3.HeadObject(bucket, key)
+	if err == client.ErrObjectNotFound {
+		return nil, info, errors.New("No such file or directory: " + displayPath(p))
+	} else if err != nil {
+		return nil, info, err
+	}
+
+	return p, info, nil
+}
+
+// displayPath returns the absolute, human-readable form of a path calculated by a context.
+func displayPath(p []string) string {
+	return context.PathDelimiter + strings.Join(p, context.PathDelimiter)
+}
+
+// bucketName validates and returns the bucket named by an argument, which may include leading or trailing path
+// delimiters but must not contain a folder.
+func bucketName(arg string) (string, error) {
+	name := strings.Trim(arg, context.PathDelimiter)
+	if len(name) == 0 || strings.Contains(name, context.PathDelimiter) {
+		return "", fmt.Errorf("Invalid bucket name: %v", arg)
+	}
+
+	return name, nil
+}
+
+// localKeyPath returns the local path within dir of an object key relative to a folder, or an error if the key,
+// such as one containing '..', would escape dir.
+func localKeyPath(dir, rel string) (string, error) {
+	rel = filepath.Clean(filepath.FromSlash(rel))
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", errors.New("Key is outside of the target folder")
+	}
+
+	return filepath.Join(dir, rel), nil
+}
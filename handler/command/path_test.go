@@ -0,0 +1,132 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+func Test_splitFolderPath(t *testing.T) {
+	tests := []struct {
+		path   []string
+		bucket string
+		prefix string
+	}{
+		{nil, "", ""},
+		{[]string{"bucket"}, "bucket", ""},
+		{[]string{"bucket", "folder"}, "bucket", "folder/"},
+		{[]string{"bucket", "folder", "sub"}, "bucket", "folder/sub/"},
+	}
+
+	for _, test := range tests {
+		if bucket, prefix := splitFolderPath(test.path); bucket != test.bucket || prefix != test.prefix {
+			t.Fatalf("Unexpected output for %v: {Expected: %v, %v, Actual: %v, %v}", test.path, test.bucket, test.prefix, bucket, prefix)
+		}
+	}
+}
+
+func Test_splitKeyPath(t *testing.T) {
+	tests := []struct {
+		path   []string
+		bucket string
+		key    string
+	}{
+		{nil, "", ""},
+		{[]string{"bucket"}, "bucket", ""},
+		{[]string{"bucket", "file.txt"}, "bucket", "file.txt"},
+		{[]string{"bucket", "folder", "file.txt"}, "bucket", "folder/file.txt"},
+	}
+
+	for _, test := range tests {
+		if bucket, key := splitKeyPath(test.path); bucket != test.bucket || key != test.key {
+			t.Fatalf("Unexpected output for %v: {Expected: %v, %v, Actual: %v, %v}", test.path, test.bucket, test.key, bucket, key)
+		}
+	}
+}
+
+func Test_folderExists(t *testing.T) {
+	var s3 mockS3Client
+	mockErr := errors.New("Mock Error")
+	s3.bucketExistsCallback = func(bucket string) (bool, error) {
+		return bucket == "bucket", nil
+	}
+	s3.pathExistsCallback = func(bucket, prefix string) (bool, error) {
+		if prefix == "error/" {
+			return false, mockErr
+		}
+		return bucket == "bucket" && prefix == "folder/", nil
+	}
+
+	tests := []struct {
+		bucket string
+		prefix string
+		exists bool
+		err    error
+	}{
+		{"bucket", "", true, nil},
+		{"fake", "", false, nil},
+		{"bucket", "folder/", true, nil},
+		{"bucket", "fake/", false, nil},
+		{"bucket", "error/", false, mockErr},
+	}
+
+	for _, test := range tests {
+		if exists, err := folderExists(&s3, test.bucket, test.prefix); exists != test.exists || err != test.err {
+			t.Fatalf("Unexpected output for %v/%v: {Expected: %v, %v, Actual: %v, %v}", test.bucket, test.prefix, test.exists, test.err, exists, err)
+		}
+	}
+}
+
+func Test_resolveFile(t *testing.T) {
+	s3 := newMockS3Listing(map[string][]client.Object{
+		"bucket": {{Key: "folder/a.txt"}},
+	})
+	var con context.Context
+	con.UpdatePath("bucket")
+
+	// Positive case
+	if p, err := resolveFile(&s3, &con, "folder/a.txt"); err != nil {
+		t.Fatal(err)
+	} else if strings.Join(p, "/") != "bucket/folder/a.txt" {
+		t.Fatalf("Unexpected path: %v", p)
+	}
+
+	// Invalid targets
+	for _, arg := range []string{"/", "/bucket", "folder", "fake.txt"} {
+		if _, err := resolveFile(&s3, &con, arg); err == nil {
+			t.Fatalf("Expected error for invalid target: %v", arg)
+		}
+	}
+}
+
+func Test_displayPath(t *testing.T) {
+	if p := displayPath(nil); p != "/" {
+		t.Fatalf("Unexpected root display path: %v", p)
+	} else if p := displayPath([]string{"bucket", "folder"}); p != "/bucket/folder" {
+		t.Fatalf("Unexpected display path: %v", p)
+	}
+}
+
+func Test_bucketName(t *testing.T) {
+	tests := []struct {
+		arg   string
+		name  string
+		valid bool
+	}{
+		{"bucket", "bucket", true},
+		{"/bucket", "bucket", true},
+		{"/bucket/", "bucket", true},
+		{"", "", false},
+		{"/", "", false},
+		{"bucket/folder", "", false},
+	}
+
+	for _, test := range tests {
+		if name, err := bucketName(test.arg); name != test.name || (err == nil) != test.valid {
+			t.Fatalf("Unexpected output for %q: {Expected: %v, %v, Actual: %v, %v}", test.arg, test.name, test.valid, name, err)
+		}
+	}
+}
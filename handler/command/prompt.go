@@ -0,0 +1,28 @@
+package command
+
+import (
+	"strings"
+)
+
+const (
+	// confirmSuffix is appended to confirmation prompts to indicate the expected input, and the default.
+	confirmSuffix = " [y/N] "
+)
+
+// confirm prompts the user with a question and returns true if they respond affirmatively.
+//
+// Any response other than 'y' or 'yes', including no response at all, is treated as a refusal.
+func confirm(out Outputter, in Inputter, question string) bool {
+	out.Write("\n" + question + confirmSuffix)
+
+	if in == nil || !in.Scan() {
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(in.Text())) {
+	case "y", "yes":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,40 @@
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_confirm(t *testing.T) {
+	tests := []struct {
+		input    []string
+		expected bool
+	}{
+		{[]string{"y"}, true},
+		{[]string{"Y"}, true},
+		{[]string{" yes "}, true},
+		{[]string{"n"}, false},
+		{[]string{""}, false},
+		{[]string{"maybe"}, false},
+		{nil, false},
+	}
+
+	for _, test := range tests {
+		var out mockOutputter
+		in := mockInputter{lines: test.input}
+
+		if res := confirm(&out, &in, "Question?"); res != test.expected {
+			t.Fatalf("Unexpected confirmation for %v: {Expected: %v, Actual: %v}", test.input, test.expected, res)
+		} else if len(out.output) != 1 || !strings.Contains(out.output[0], "Question?"+confirmSuffix) {
+			t.Fatalf("Unexpected prompt output: %v", out.output)
+		}
+	}
+
+	// No Inputter
+	{
+		var out mockOutputter
+		if confirm(&out, nil, "Question?") {
+			t.Fatal("Expected refusal without an Inputter")
+		}
+	}
+}
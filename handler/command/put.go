@@ -2,9 +2,14 @@ package command
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/KyleBanks/s3fs/client"
 	"github.com/KyleBanks/s3fs/handler/command/context"
 	"github.com/KyleBanks/s3fs/handler/command/util"
 )
@@ -15,6 +20,22 @@ const (
 
 	// putArgsIndexDestination indicates the expected argument index for the destination file location.
 	putArgsIndexDestination = 1
+
+	// putFlagRecursive indicates that a local directory, and every file within it, should be uploaded.
+	putFlagRecursive = "r"
+
+	// putFlagExclude provides a pattern of file and directory names, or relative paths, that should not be
+	// uploaded. It may be provided more than once.
+	putFlagExclude = "exclude"
+
+	// putFlagPartSize provides the size of each part when uploading large files in parts, such as '64M'.
+	putFlagPartSize = "part-size"
+
+	// putFlagConcurrency provides the number of parts of each large file uploaded at once.
+	putFlagConcurrency = "concurrency"
+
+	// putConcurrency is the number of files uploaded at once when uploading a directory.
+	putConcurrency = 8
 )
 
 // PutCommand uploads an object.
@@ -23,30 +44,47 @@ type PutCommand struct {
 	con *context.Context
 
 	args []string
+
+	interrupt chan os.Signal
+}
+
+// putJob is a local file to be uploaded to a key within a directory upload.
+type putJob struct {
+	path string
+	key  string
+}
+
+// putResult is the outcome of uploading a single file within a directory.
+type putResult struct {
+	path string
+	size int64
+	err  error
 }
 
 // Execute performs a 'put' command by uploading a file to S3.
+//
+// When recursive, a directory is uploaded by mapping the relative path of each file to a key within the destination.
+// Large files are uploaded in parts, and uploads that are interrupted can be resumed by performing the same 'put' again.
 func (p PutCommand) Execute(out Outputter) error {
-	// Get the target to upload from the input arguments.
-	if len(p.args) < putArgsIndexTarget+1 {
-		return errors.New("Missing target file.")
-	}
-	target, err := util.AbsPath(p.args[putArgsIndexTarget])
+	flags := util.ParseFlags(p.args, putFlagExclude, putFlagPartSize, putFlagConcurrency)
+	opts, err := uploadOptions(flags)
 	if err != nil {
 		return err
 	}
 
-	// Open the target file.
-	file, err := os.Open(target)
+	// Get the target to upload from the input arguments.
+	if len(flags.Args) < putArgsIndexTarget+1 {
+		return errors.New("Missing target file.")
+	}
+	target, err := util.AbsPath(flags.Args[putArgsIndexTarget])
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
 	// Get the (optional) destination.
 	var destination string
-	if len(p.args) >= putArgsIndexDestination+1 {
-		destination = p.args[putArgsIndexDestination]
+	if len(flags.Args) >= putArgsIndexDestination+1 {
+		destination = flags.Args[putArgsIndexDestination]
 	}
 
 	// Determine the S3 destination.
@@ -55,8 +93,30 @@ func (p PutCommand) Execute(out Outputter) error {
 		return errors.New("Missing destination bucket.")
 	}
 
+	info, err := os.Stat(target)
+	if err != nil {
+		return err
+	} else if info.IsDir() && !flags.Has(putFlagRecursive) {
+		return fmt.Errorf("Target is a directory, use -r to upload it: %v", target)
+	}
+
+	cancel, stop := cancelOnInterrupt(p.interrupt)
+	defer stop()
+	opts.Cancel = cancel
+
+	if info.IsDir() {
+		return p.putDir(out, target, path, flags.Values(putFlagExclude), opts)
+	}
+
+	// Open the target file.
+	file, err := os.Open(target)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
 	// Upload the object.
-	uploadKey, err := p.s3.UploadObject(path[0], strings.Join(path[1:], context.PathDelimiter), file)
+	uploadKey, err := p.s3.UploadObject(path[0], strings.Join(path[1:], context.PathDelimiter), file, opts)
 	if err != nil {
 		return err
 	}
@@ -65,6 +125,136 @@ func (p PutCommand) Execute(out Outputter) error {
 	return nil
 }
 
+// putDir uploads every file within a local directory concurrently, skipping those that match an exclude pattern,
+// and outputs a summary of the upload.
+//
+// Following the semantics of 'cp -r', if the destination is an existing folder the directory is uploaded into it
+// using its own name, otherwise the destination becomes the folder.
+func (p PutCommand) putDir(out Outputter, target string, path []string, excludes []string, opts client.UploadOptions) error {
+	for _, pattern := range excludes {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("Invalid exclude pattern: %v", pattern)
+		}
+	}
+
+	bucket, prefix := splitFolderPath(path)
+	if ok, err := folderExists(p.s3, bucket, prefix); err != nil {
+		return err
+	} else if ok {
+		prefix = prefix + filepath.Base(target) + context.PathDelimiter
+	} else if len(path) == 1 {
+		return errors.New("No such bucket: " + displayPath(path))
+	}
+
+	jobs := make(chan putJob)
+	results := make(chan putResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < putConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- p.upload(bucket, j, opts)
+			}
+		}()
+	}
+
+	var excluded int
+	go func() {
+		filepath.Walk(target, func(local string, info os.FileInfo, err error) error {
+			if err != nil {
+				results <- putResult{path: local, err: err}
+				return nil
+			}
+
+			// Stop queueing files once interrupted.
+			select {
+			case <-opts.Cancel:
+				return client.ErrUploadInterrupted
+			default:
+			}
+
+			rel, err := filepath.Rel(target, local)
+			if err != nil {
+				results <- putResult{path: local, err: err}
+				return nil
+			} else if rel == "." {
+				return nil
+			}
+
+			if isExcluded(rel, excludes) {
+				excluded++
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			} else if info.IsDir() {
+				return nil
+			}
+
+			jobs <- putJob{path: local, key: prefix + filepath.ToSlash(rel)}
+			return nil
+		})
+
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	var uploaded, failed int
+	var size int64
+	for r := range results {
+		if r.err == client.ErrUploadInterrupted {
+			continue
+		} else if r.err != nil {
+			failed++
+			out.Write(fmt.Sprintf("\nFailed to upload: %v: %v", r.path, r.err))
+			continue
+		}
+
+		uploaded++
+		size += r.size
+	}
+
+	out.Write(fmt.Sprintf("\nUploaded %d file(s) (%v), excluded %d: %v -> %v",
+		uploaded, util.HumanSize(size), excluded, target, displayPath([]string{bucket, prefix})))
+
+	select {
+	case <-opts.Cancel:
+		return client.ErrUploadInterrupted
+	default:
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Failed to upload %d file(s)", failed)
+	}
+
+	return nil
+}
+
+// upload uploads a single local file within a directory to its key.
+func (p PutCommand) upload(bucket string, j putJob, opts client.UploadOptions) putResult {
+	res := putResult{path: j.path}
+
+	file, err := os.Open(j.path)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		res.err = err
+		return res
+	}
+	res.size = info.Size()
+
+	res.err = p.s3.PutObject(bucket, j.key, file, opts)
+	return res
+}
+
 // IsLongRunning returns true because 'put' must always perform network requests.
 func (PutCommand) IsLongRunning() bool {
 	return true
@@ -78,3 +268,51 @@ func NewPut(s3 S3Client, con *context.Context, args []string) PutCommand {
 		args: args,
 	}
 }
+
+// isExcluded returns true if the name of a relative path, or the entire path, matches any of the patterns.
+func isExcluded(rel string, patterns []string) bool {
+	name := filepath.Base(rel)
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		} else if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// uploadOptions returns the options for uploading large files in parts, as provided by flags.
+func uploadOptions(flags util.Flags) (client.UploadOptions, error) {
+	var opts client.UploadOptions
+	var err error
+
+	opts.PartSize, opts.Concurrency, err = partOptions(flags, putFlagPartSize, putFlagConcurrency)
+	return opts, err
+}
+
+// partOptions returns the part size and concurrency of large transfers provided by the named flags, each of which
+// is zero if not provided.
+func partOptions(flags util.Flags, partSizeFlag, concurrencyFlag string) (int64, int, error) {
+	var size int64
+	var n int
+	var err error
+
+	if flags.Has(partSizeFlag) {
+		size, err = util.ParseSize(flags.Value(partSizeFlag))
+		if err != nil || size < client.MinPartSize || size > client.MaxPartSize {
+			return 0, 0, fmt.Errorf("Invalid part size, must be between %v and %v: %v",
+				util.HumanSize(client.MinPartSize), util.HumanSize(client.MaxPartSize), flags.Value(partSizeFlag))
+		}
+	}
+
+	if flags.Has(concurrencyFlag) {
+		n, err = strconv.Atoi(flags.Value(concurrencyFlag))
+		if err != nil || n < 1 {
+			return 0, 0, fmt.Errorf("Invalid concurrency: %v", flags.Value(concurrencyFlag))
+		}
+	}
+
+	return size, n, nil
+}
@@ -4,8 +4,14 @@ import (
 	"errors"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/KyleBanks/s3fs/client"
 	"github.com/KyleBanks/s3fs/handler/command/context"
 )
 
@@ -20,7 +26,7 @@ func TestPutCommand_Execute(t *testing.T) {
 		defer os.Remove(file.Name())
 		args := []string{file.Name()}
 
-		s3.uploadObjectCallback = func(bucket, key string, f *os.File) (string, error) {
+		s3.uploadObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) (string, error) {
 			if bucket != "bucket" || key != "folder" || f.Name() != file.Name() {
 				t.Fatalf("Unexpected input to UploadObject(%v, %v, %v)", bucket, key, f.Name())
 			}
@@ -44,7 +50,7 @@ func TestPutCommand_Execute(t *testing.T) {
 		defer os.Remove(file.Name())
 		args := []string{file.Name(), "folder/subfolder"}
 
-		s3.uploadObjectCallback = func(bucket, key string, f *os.File) (string, error) {
+		s3.uploadObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) (string, error) {
 			if bucket != "bucket" || key != "folder/subfolder" || f.Name() != file.Name() {
 				t.Fatalf("Unexpected input to UploadObject(%v, %v, %v)", bucket, key, f.Name())
 			}
@@ -69,7 +75,7 @@ func TestPutCommand_Execute(t *testing.T) {
 		args := []string{file.Name()}
 		mockErr := errors.New("Mock Err")
 
-		s3.uploadObjectCallback = func(bucket, key string, f *os.File) (string, error) {
+		s3.uploadObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) (string, error) {
 			return "", mockErr
 		}
 
@@ -108,6 +114,270 @@ func TestPutCommand_Execute(t *testing.T) {
 	}
 }
 
+func TestPutCommand_Execute_recursive(t *testing.T) {
+	// Create a local tree to upload.
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	for name, contents := range map[string]string{
+		"a.txt":           "a",
+		"sub/b.txt":       "bb",
+		"sub/deep/c.txt":  "ccc",
+		"build/out.o":     "obj",
+		".git/HEAD":       "ref",
+		"sub/ignored.log": "log",
+	} {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "existing/file.txt"}},
+	}
+
+	// mockS3 returns a client that records the bucket and key of each upload.
+	mockS3 := func(uploaded *[]string) mockS3Client {
+		var mu sync.Mutex
+		s3 := newMockS3Listing(buckets)
+		s3.putObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) error {
+			mu.Lock()
+			defer mu.Unlock()
+			*uploaded = append(*uploaded, bucket+"/"+key)
+			return nil
+		}
+		return s3
+	}
+
+	tests := []struct {
+		args     []string
+		expected []string
+		output   string
+	}{
+		{[]string{"-r", src}, []string{
+			"bucket/existing/src/.git/HEAD",
+			"bucket/existing/src/a.txt",
+			"bucket/existing/src/build/out.o",
+			"bucket/existing/src/sub/b.txt",
+			"bucket/existing/src/sub/deep/c.txt",
+			"bucket/existing/src/sub/ignored.log",
+		}, "\nUploaded 6 file(s) (15), excluded 0: " + src + " -> /bucket/existing/src/"},
+		{[]string{"-r", src, "/bucket/new", "--exclude", ".git", "--exclude=build", "--exclude", "*.log"}, []string{
+			"bucket/new/a.txt",
+			"bucket/new/sub/b.txt",
+			"bucket/new/sub/deep/c.txt",
+		}, "\nUploaded 3 file(s) (6), excluded 3: " + src + " -> /bucket/new/"},
+		{[]string{"-r", src, "/bucket", "--exclude", "sub/deep"}, []string{
+			"bucket/src/.git/HEAD",
+			"bucket/src/a.txt",
+			"bucket/src/build/out.o",
+			"bucket/src/sub/b.txt",
+			"bucket/src/sub/ignored.log",
+		}, "\nUploaded 5 file(s) (12), excluded 1: " + src + " -> /bucket/src/"},
+	}
+
+	for _, test := range tests {
+		var uploaded []string
+		s3 := mockS3(&uploaded)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket/existing")
+
+		put := NewPut(&s3, &con, test.args)
+		if err := put.Execute(&out); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.args, err)
+		}
+
+		sort.Strings(uploaded)
+		if !reflect.DeepEqual(uploaded, test.expected) {
+			t.Fatalf("Unexpected uploads for %v: {Expected: %v, Actual: %v}", test.args, test.expected, uploaded)
+		} else if output := strings.Join(out.output, ""); output != test.output {
+			t.Fatalf("Unexpected output for %v: {Expected: %q, Actual: %q}", test.args, test.output, output)
+		}
+	}
+
+	// Negative: Invalid args
+	for _, args := range [][]string{
+		{src},
+		{"-r", src, "/fake"},
+		{"-r", src, "/bucket", "--exclude", "[a-"},
+	} {
+		var uploaded []string
+		s3 := mockS3(&uploaded)
+		var con context.Context
+		var out mockOutputter
+
+		put := NewPut(&s3, &con, args)
+		if err := put.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		} else if len(uploaded) > 0 {
+			t.Fatalf("Unexpected uploads for invalid args %v: %v", args, uploaded)
+		}
+	}
+
+	// Negative: S3 error, which does not prevent the other files from being uploaded
+	{
+		mockErr := errors.New("Mock Error")
+		var uploaded []string
+		s3 := mockS3(&uploaded)
+		upload := s3.putObjectCallback
+		s3.putObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) error {
+			if strings.HasSuffix(key, "a.txt") {
+				return mockErr
+			}
+			return upload(bucket, key, f, opts)
+		}
+		var con context.Context
+		var out mockOutputter
+
+		put := NewPut(&s3, &con, []string{"-r", src, "/bucket/new"})
+		if err := put.Execute(&out); err == nil {
+			t.Fatal("Expected error when an upload fails")
+		} else if len(uploaded) != 5 {
+			t.Fatalf("Unexpected uploads: %v", uploaded)
+		}
+
+		expected := "\nFailed to upload: " + filepath.Join(src, "a.txt") + ": Mock Error"
+		if output := strings.Join(out.output, ""); !strings.HasPrefix(output, expected) {
+			t.Fatalf("Unexpected output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+}
+
+func TestPutCommand_Execute_multipart(t *testing.T) {
+	file, _ := ioutil.TempFile("", "")
+	defer os.Remove(file.Name())
+
+	// Options
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.uploadObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) (string, error) {
+			if opts.PartSize != 64*1024*1024 || opts.Concurrency != 3 || opts.Cancel == nil {
+				t.Fatalf("Unexpected UploadOptions: %v", opts)
+			}
+			return key, nil
+		}
+
+		put := NewPut(&s3, &con, []string{file.Name(), "--part-size", "64M", "--concurrency=3"})
+		if err := put.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Defaults
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.uploadObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) (string, error) {
+			if opts.PartSize != 0 || opts.Concurrency != 0 {
+				t.Fatalf("Unexpected UploadOptions: %v", opts)
+			}
+			return key, nil
+		}
+
+		put := NewPut(&s3, &con, []string{file.Name()})
+		if err := put.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Invalid options
+	for _, args := range [][]string{
+		{file.Name(), "--part-size", "1M"},
+		{file.Name(), "--part-size", "6G"},
+		{file.Name(), "--part-size", "abc"},
+		{file.Name(), "--concurrency", "0"},
+		{file.Name(), "--concurrency", "abc"},
+	} {
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		put := NewPut(&s3, &con, args)
+		if err := put.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+}
+
+func TestPutCommand_Execute_interrupt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644)
+	}
+
+	// interrupted sends an interrupt, and waits for the upload to be cancelled.
+	interrupted := func(interrupt chan os.Signal, opts client.UploadOptions) error {
+		select {
+		case interrupt <- os.Interrupt:
+		default:
+		}
+
+		<-opts.Cancel
+		return client.ErrUploadInterrupted
+	}
+
+	// Single file
+	{
+		interrupt := make(chan os.Signal, 1)
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.uploadObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) (string, error) {
+			return "", interrupted(interrupt, opts)
+		}
+
+		put := NewPut(&s3, &con, []string{filepath.Join(dir, "a.txt")})
+		put.interrupt = interrupt
+		if err := put.Execute(&out); err != client.ErrUploadInterrupted {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// Directory
+	{
+		interrupt := make(chan os.Signal, 1)
+		s3 := newMockS3Listing(map[string][]client.Object{"bucket": nil})
+		var con context.Context
+		var out mockOutputter
+
+		s3.putObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) error {
+			return interrupted(interrupt, opts)
+		}
+
+		put := NewPut(&s3, &con, []string{"-r", dir, "/bucket/folder"})
+		put.interrupt = interrupt
+		if err := put.Execute(&out); err != client.ErrUploadInterrupted {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := "\nUploaded 0 file(s) (0), excluded 0: " + dir + " -> /bucket/folder/"
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+}
+
 func TestPutCommand_IsLongRunning(t *testing.T) {
 	put := PutCommand{}
 
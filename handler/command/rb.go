@@ -0,0 +1,135 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// rbFlagForce indicates that a non-empty bucket should be emptied before it is removed.
+	rbFlagForce = "force"
+)
+
+// RbCommand removes a bucket.
+type RbCommand struct {
+	s3  S3Client
+	con *context.Context
+
+	args []string
+}
+
+// Execute performs an 'rb' command by deleting the named bucket.
+//
+// Buckets that contain any objects, object versions or delete markers are refused unless forced, in which case
+// every version and delete marker is permanently deleted before the bucket is removed.
+func (rb RbCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(rb.args)
+	if len(flags.Args) == 0 {
+		return errors.New("Missing bucket name.")
+	}
+
+	name, err := bucketName(flags.Args[0])
+	if err != nil {
+		return err
+	}
+	p := []string{name}
+
+	if ok, err := rb.s3.BucketExists(name); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("No such bucket: " + displayPath(p))
+	}
+
+	if flags.Has(rbFlagForce) {
+		if err := rb.empty(out, name); err != nil {
+			return err
+		}
+	} else if empty, err := rb.isEmpty(name); err != nil {
+		return err
+	} else if !empty {
+		return fmt.Errorf("Bucket is not empty, use --%v to remove every object and version: %v", rbFlagForce, displayPath(p))
+	}
+
+	if err := rb.s3.DeleteBucket(name); err != nil {
+		return err
+	}
+
+	// Move back to the root if the context was within the removed bucket.
+	if rb.con.Bucket() == name {
+		rb.con.UpdatePath(context.PathDelimiter)
+	}
+
+	out.Write("\nRemoved bucket: " + displayPath(p))
+	return nil
+}
+
+// isEmpty returns a bool indicating if the bucket contains no object versions or delete markers.
+func (rb RbCommand) isEmpty(bucket string) (bool, error) {
+	empty := true
+	err := rb.s3.LsObjectVersions(bucket, "", func(versions []client.ObjectVersion) bool {
+		empty = len(versions) == 0
+		return empty
+	})
+
+	return empty, err
+}
+
+// empty permanently deletes every object version and delete marker in the bucket, one page of the listing at a
+// time, and outputs the running count of removed versions along with any that could not be removed.
+func (rb RbCommand) empty(out Outputter, bucket string) error {
+	p := []string{bucket}
+
+	var removed int
+	var failed []client.DeleteError
+	var deleteErr error
+
+	err := rb.s3.LsObjectVersions(bucket, "", func(versions []client.ObjectVersion) bool {
+		if len(versions) == 0 {
+			return true
+		}
+
+		f, err := rb.s3.DeleteObjectVersions(bucket, versions)
+		if err != nil {
+			deleteErr = err
+			return false
+		}
+
+		removed += len(versions) - len(f)
+		failed = append(failed, f...)
+		out.Write(fmt.Sprintf("\nRemoved %d object version(s) from %v...", removed, displayPath(p)))
+		return true
+	})
+	if err != nil {
+		return err
+	} else if deleteErr != nil {
+		return deleteErr
+	}
+
+	for _, f := range failed {
+		out.Write("\nFailed to remove: " + f.Error())
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("Failed to remove %d object version(s), the bucket has not been removed: %v", len(failed), displayPath(p))
+	}
+
+	return nil
+}
+
+// IsLongRunning returns true because 'rb' requires network operations.
+func (RbCommand) IsLongRunning() bool {
+	return true
+}
+
+// NewRb initializes and returns an RbCommand.
+func NewRb(s3 S3Client, con *context.Context, args []string) RbCommand {
+	return RbCommand{
+		s3:   s3,
+		con:  con,
+		args: args,
+	}
+}
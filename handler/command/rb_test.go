@@ -0,0 +1,225 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+// mockLsObjectVersions returns an LsObjectVersions callback that provides the versions in pages of the size
+// specified.
+func mockLsObjectVersions(versions []client.ObjectVersion, pageSize int) func(string, string, func([]client.ObjectVersion) bool) error {
+	return func(bucket, prefix string, fn func([]client.ObjectVersion) bool) error {
+		if len(versions) == 0 {
+			fn(nil)
+			return nil
+		}
+
+		for start := 0; start < len(versions); start += pageSize {
+			end := start + pageSize
+			if end > len(versions) {
+				end = len(versions)
+			}
+
+			if !fn(versions[start:end]) {
+				break
+			}
+		}
+		return nil
+	}
+}
+
+func TestRbCommand_Execute(t *testing.T) {
+	// Empty bucket, moving the context back to root
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket/folder")
+
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return true, nil
+		}
+		s3.lsObjectVersionsCallback = mockLsObjectVersions(nil, 1)
+		s3.deleteObjectVersionsCallback = func(bucket string, versions []client.ObjectVersion) ([]client.DeleteError, error) {
+			t.Fatal("DeleteObjectVersions should not be called without --force")
+			return nil, nil
+		}
+
+		var deleted string
+		s3.deleteBucketCallback = func(bucket string) error {
+			deleted = bucket
+			return nil
+		}
+
+		rb := NewRb(&s3, &con, []string{"bucket"})
+		if err := rb.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if deleted != "bucket" {
+			t.Fatalf("Unexpected bucket deleted: %v", deleted)
+		} else if !con.IsRoot() {
+			t.Fatalf("Expected context to be moved to root: %v", con.Path())
+		} else if len(out.output) != 1 || !strings.Contains(out.output[0], "Removed bucket: /bucket") {
+			t.Fatalf("Unexpected output: %v", out.output)
+		}
+	}
+
+	// Forced, with versions and delete markers
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("other")
+
+		versions := []client.ObjectVersion{
+			{Key: "a.txt", VersionID: "v1"},
+			{Key: "a.txt", VersionID: "v2"},
+			{Key: "b.txt", VersionID: "v3", IsDeleteMarker: true},
+		}
+
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return true, nil
+		}
+		s3.lsObjectVersionsCallback = mockLsObjectVersions(versions, 2)
+
+		var removed []client.ObjectVersion
+		s3.deleteObjectVersionsCallback = func(bucket string, v []client.ObjectVersion) ([]client.DeleteError, error) {
+			removed = append(removed, v...)
+			return nil, nil
+		}
+
+		var deleted bool
+		s3.deleteBucketCallback = func(bucket string) error {
+			if len(removed) != len(versions) {
+				t.Fatal("Expected every version to be removed before the bucket")
+			}
+			deleted = true
+			return nil
+		}
+
+		rb := NewRb(&s3, &con, []string{"--force", "/bucket"})
+		if err := rb.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if !deleted {
+			t.Fatal("Expected bucket to be deleted")
+		} else if con.Path() != "other" {
+			t.Fatalf("Expected context to be unchanged: %v", con.Path())
+		}
+
+		for i := range versions {
+			if removed[i] != versions[i] {
+				t.Fatalf("Unexpected version removed: {Expected: %v, Actual: %v}", versions[i], removed[i])
+			}
+		}
+
+		expected := "\nRemoved 2 object version(s) from /bucket...\nRemoved 3 object version(s) from /bucket...\nRemoved bucket: /bucket"
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+}
+
+func TestRbCommand_Execute_invalid(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"/"},
+		{"bucket/folder"},
+		{"fake"},
+		{"nonempty"},
+	} {
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return bucket != "fake", nil
+		}
+		s3.lsObjectVersionsCallback = mockLsObjectVersions([]client.ObjectVersion{{Key: "a.txt", IsDeleteMarker: true}}, 1)
+		s3.deleteBucketCallback = func(bucket string) error {
+			t.Fatalf("DeleteBucket should not be called for invalid args: %v", args)
+			return nil
+		}
+
+		rb := NewRb(&s3, &con, args)
+		if err := rb.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+
+	// Failed version removals leave the bucket
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return true, nil
+		}
+		s3.lsObjectVersionsCallback = mockLsObjectVersions([]client.ObjectVersion{{Key: "a.txt", VersionID: "v1"}}, 1)
+		s3.deleteObjectVersionsCallback = func(bucket string, v []client.ObjectVersion) ([]client.DeleteError, error) {
+			return []client.DeleteError{{Key: "a.txt", Code: "AccessDenied", Message: "Access Denied"}}, nil
+		}
+		s3.deleteBucketCallback = func(bucket string) error {
+			t.Fatal("DeleteBucket should not be called when versions fail to be removed")
+			return nil
+		}
+
+		rb := NewRb(&s3, &con, []string{"--force", "bucket"})
+		if err := rb.Execute(&out); err == nil {
+			t.Fatal("Expected error when versions fail to be removed")
+		} else if !strings.Contains(strings.Join(out.output, ""), "Failed to remove: a.txt") {
+			t.Fatalf("Expected failures to be output: %v", out.output)
+		}
+	}
+
+	// S3 error
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		mockErr := errors.New("Mock Error")
+
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return true, nil
+		}
+		s3.lsObjectVersionsCallback = mockLsObjectVersions(nil, 1)
+		s3.deleteBucketCallback = func(bucket string) error {
+			return mockErr
+		}
+
+		rb := NewRb(&s3, &con, []string{"bucket"})
+		if err := rb.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestRbCommand_IsLongRunning(t *testing.T) {
+	rb := NewRb(nil, nil, nil)
+
+	if !rb.IsLongRunning() {
+		t.Fatal("Expected RbCommand to always be long running")
+	}
+}
+
+func TestNewRb(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	args := []string{"bucket"}
+
+	rb := NewRb(&s3, &con, args)
+	if rb.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on rb command: %v", rb.s3)
+	} else if rb.con != &con {
+		t.Fatalf("Unexpected Context stored on rb command: %v", rb.con)
+	} else if rb.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on rb command: %v", rb.args)
+	}
+}
@@ -0,0 +1,187 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// resumeFlagList indicates that the transfers should be listed, rather than resumed.
+	resumeFlagList = "list"
+
+	// resumeFlagDiscard indicates that the transfers should be abandoned, rather than resumed.
+	resumeFlagDiscard = "discard"
+)
+
+// ResumeCommand resumes uploads and downloads that stopped before completing.
+type ResumeCommand struct {
+	s3 S3Client
+	in Inputter
+
+	args []string
+
+	interrupt chan os.Signal
+}
+
+// Execute performs a 'resume' command by resuming, listing or discarding the transfers recorded in the journal.
+//
+// Transfers may be selected by their number in the list, otherwise every transfer is selected.
+func (r ResumeCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(r.args)
+
+	transfers, err := r.s3.Transfers()
+	if err != nil {
+		return err
+	} else if len(transfers) == 0 {
+		out.Write("\nNo transfers to resume")
+		return nil
+	}
+
+	if flags.Has(resumeFlagList) {
+		for i, t := range transfers {
+			out.Write(fmt.Sprintf("\n%d. %v (%v of %v)", i+1, transferDescription(t),
+				util.HumanSize(transferProgress(t)), util.HumanSize(t.Size)))
+		}
+		return nil
+	}
+
+	selected, err := selectTransfers(transfers, flags.Args)
+	if err != nil {
+		return err
+	}
+
+	if flags.Has(resumeFlagDiscard) {
+		return r.discard(out, selected)
+	}
+
+	cancel, stop := cancelOnInterrupt(r.interrupt)
+	defer stop()
+
+	var failed int
+	for _, t := range selected {
+		err := r.resume(t, cancel)
+		if err == client.ErrUploadInterrupted || err == client.ErrDownloadInterrupted {
+			return err
+		} else if err != nil {
+			failed++
+			out.Write(fmt.Sprintf("\nFailed to resume: %v: %v", transferDescription(t), err))
+			continue
+		}
+
+		out.Write("\nResumed: " + transferDescription(t))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("Failed to resume %d transfer(s)", failed)
+	}
+
+	return nil
+}
+
+// resume resumes a single transfer until it completes, fails or is cancelled.
+func (r ResumeCommand) resume(t client.Transfer, cancel <-chan struct{}) error {
+	if t.Kind == client.TransferDownload {
+		return r.s3.DownloadFile(t.Bucket, t.Key, t.Path, client.DownloadOptions{Cancel: cancel})
+	}
+
+	file, err := os.Open(t.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// A file that has changed is uploaded again from the start, which may no longer require a multipart upload, so
+	// the parts already uploaded are discarded rather than left behind.
+	if info, err := file.Stat(); err != nil {
+		return err
+	} else if info.Size() != t.Size || !info.ModTime().Equal(t.ModTime) {
+		if err := r.s3.DiscardTransfer(t); err != nil {
+			return err
+		}
+	}
+
+	return r.s3.PutObject(t.Bucket, t.Key, file, client.UploadOptions{PartSize: t.PartSize, Cancel: cancel})
+}
+
+// discard abandons transfers after confirmation.
+func (r ResumeCommand) discard(out Outputter, transfers []client.Transfer) error {
+	for _, t := range transfers {
+		out.Write("\n" + transferDescription(t))
+	}
+
+	if !confirm(out, r.in, fmt.Sprintf("Discard %d transfer(s)?", len(transfers))) {
+		return nil
+	}
+
+	for _, t := range transfers {
+		if err := r.s3.DiscardTransfer(t); err != nil {
+			return err
+		}
+		out.Write("\nDiscarded: " + transferDescription(t))
+	}
+
+	return nil
+}
+
+// IsLongRunning returns true when resuming transfers, as listing is performed locally and discarding requires
+// confirmation, which the loading indicator would interfere with.
+func (r ResumeCommand) IsLongRunning() bool {
+	flags := util.ParseFlags(r.args)
+	return !flags.Has(resumeFlagList) && !flags.Has(resumeFlagDiscard)
+}
+
+// NewResume initializes and returns a ResumeCommand.
+func NewResume(s3 S3Client, in Inputter, args []string) ResumeCommand {
+	return ResumeCommand{
+		s3:   s3,
+		in:   in,
+		args: args,
+	}
+}
+
+// selectTransfers returns the transfers with the numbers provided, starting from one, or every transfer if no
+// numbers are provided.
+func selectTransfers(transfers []client.Transfer, args []string) ([]client.Transfer, error) {
+	if len(args) == 0 {
+		return transfers, nil
+	}
+
+	selected := make([]client.Transfer, len(args))
+	for i, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > len(transfers) {
+			return nil, fmt.Errorf("Invalid transfer, must be between 1 and %d: %v", len(transfers), arg)
+		}
+		selected[i] = transfers[n-1]
+	}
+
+	return selected, nil
+}
+
+// transferDescription returns a description of the source and destination of a transfer.
+func transferDescription(t client.Transfer) string {
+	remote := displayPath([]string{t.Bucket, t.Key})
+	if t.Kind == client.TransferUpload {
+		return "Upload: " + t.Path + " -> " + remote
+	}
+
+	return "Download: " + remote + " -> " + t.Path
+}
+
+// transferProgress returns the number of bytes transferred before a transfer stopped, which is counted in parts
+// for uploads and for downloads performed in ranges.
+func transferProgress(t client.Transfer) int64 {
+	if t.PartSize == 0 {
+		return t.Offset
+	}
+
+	progress := int64(len(t.Parts)) * t.PartSize
+	if progress > t.Size {
+		progress = t.Size
+	}
+	return progress
+}
@@ -0,0 +1,322 @@
+package command
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/s3fs/client"
+)
+
+// resumeTransfers returns an upload of a local file, and a download of an object.
+func resumeTransfers(path string) []client.Transfer {
+	return []client.Transfer{
+		{
+			Kind:     client.TransferUpload,
+			Bucket:   "bucket",
+			Key:      "backup.tar",
+			Path:     path,
+			Size:     100,
+			UploadID: "id",
+			PartSize: 40,
+			Parts:    []client.TransferPart{{Number: 1, ETag: "etag"}},
+		},
+		{
+			Kind:   client.TransferDownload,
+			Bucket: "bucket",
+			Key:    "folder/file.txt",
+			Path:   "/tmp/file.txt",
+			Size:   100,
+			Offset: 25,
+		},
+	}
+}
+
+func TestResumeCommand_Execute(t *testing.T) {
+	file, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(make([]byte, 100)); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	// The upload is of the file as it is.
+	info, err := os.Stat(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfers := resumeTransfers(file.Name())
+	transfers[0].ModTime = info.ModTime()
+
+	// Positive case
+	{
+		var s3 mockS3Client
+		var out mockOutputter
+		var resumed []string
+
+		s3.transfersCallback = func() ([]client.Transfer, error) {
+			return transfers, nil
+		}
+		s3.discardTransferCallback = func(tr client.Transfer) error {
+			t.Fatalf("Unexpected discard of an unchanged transfer: %v", tr)
+			return nil
+		}
+		s3.putObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) error {
+			if bucket != "bucket" || key != "backup.tar" || f.Name() != file.Name() || opts.PartSize != 40 || opts.Cancel == nil {
+				t.Fatalf("Unexpected upload resumed: %v %v %v %v", bucket, key, f.Name(), opts)
+			}
+			resumed = append(resumed, key)
+			return nil
+		}
+		s3.downloadFileCallback = func(bucket, key, path string, opts client.DownloadOptions) error {
+			if bucket != "bucket" || key != "folder/file.txt" || path != "/tmp/file.txt" || opts.Cancel == nil {
+				t.Fatalf("Unexpected download resumed: %v %v %v %v", bucket, key, path, opts)
+			}
+			resumed = append(resumed, key)
+			return nil
+		}
+
+		r := NewResume(&s3, nil, []string{})
+		if err := r.Execute(&out); err != nil {
+			t.Fatal(err)
+		} else if len(resumed) != 2 {
+			t.Fatalf("Unexpected transfers resumed: %v", resumed)
+		}
+
+		output := strings.Join(out.output, "")
+		for _, expected := range []string{
+			"Resumed: Upload: " + file.Name() + " -> /bucket/backup.tar",
+			"Resumed: Download: /bucket/folder/file.txt -> /tmp/file.txt",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Fatalf("Expected output to contain %q: %v", expected, output)
+			}
+		}
+
+		// A single transfer, by number
+		resumed = nil
+		r = NewResume(&s3, nil, []string{"2"})
+		if err := r.Execute(&out); err != nil {
+			t.Fatal(err)
+		} else if len(resumed) != 1 || resumed[0] != "folder/file.txt" {
+			t.Fatalf("Unexpected transfers resumed: %v", resumed)
+		}
+	}
+
+	// Changed file
+	{
+		var s3 mockS3Client
+		var out mockOutputter
+		var calls []string
+
+		changed := resumeTransfers(file.Name())[:1]
+		changed[0].Size = 1000
+		s3.transfersCallback = func() ([]client.Transfer, error) {
+			return changed, nil
+		}
+		s3.discardTransferCallback = func(tr client.Transfer) error {
+			if tr.UploadID != "id" {
+				t.Fatalf("Unexpected transfer discarded: %v", tr)
+			}
+			calls = append(calls, "discard")
+			return nil
+		}
+		s3.putObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) error {
+			calls = append(calls, "put")
+			return nil
+		}
+
+		r := NewResume(&s3, nil, []string{})
+		if err := r.Execute(&out); err != nil {
+			t.Fatal(err)
+		} else if strings.Join(calls, ",") != "discard,put" {
+			t.Fatalf("Expected the transfer to be discarded before uploading again: %v", calls)
+		}
+	}
+
+	// List
+	{
+		var s3 mockS3Client
+		var out mockOutputter
+
+		// A download performed in ranges, which is counted in parts.
+		ranged := client.Transfer{
+			Kind:     client.TransferDownload,
+			Bucket:   "bucket",
+			Key:      "large.bin",
+			Path:     "/tmp/large.bin",
+			Size:     100,
+			PartSize: 30,
+			Parts:    []client.TransferPart{{Number: 1}, {Number: 3}},
+		}
+		s3.transfersCallback = func() ([]client.Transfer, error) {
+			return append(resumeTransfers(file.Name()), ranged), nil
+		}
+
+		r := NewResume(&s3, nil, []string{"--list"})
+		if err := r.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{
+			"\n1. Upload: " + file.Name() + " -> /bucket/backup.tar (40 of 100)",
+			"\n2. Download: /bucket/folder/file.txt -> /tmp/file.txt (25 of 100)",
+			"\n3. Download: /bucket/large.bin -> /tmp/large.bin (60 of 100)",
+		}
+		if !reflect.DeepEqual(out.output, expected) {
+			t.Fatalf("Unexpected output: {Expected: %v, Actual: %v}", expected, out.output)
+		}
+	}
+
+	// No transfers
+	{
+		var s3 mockS3Client
+		var out mockOutputter
+		s3.transfersCallback = func() ([]client.Transfer, error) {
+			return nil, nil
+		}
+
+		r := NewResume(&s3, nil, []string{})
+		if err := r.Execute(&out); err != nil {
+			t.Fatal(err)
+		} else if len(out.output) != 1 || !strings.Contains(out.output[0], "No transfers to resume") {
+			t.Fatalf("Unexpected output: %v", out.output)
+		}
+	}
+
+	// Failures do not prevent the other transfers from resuming
+	{
+		var s3 mockS3Client
+		var out mockOutputter
+		mockErr := errors.New("Mock Error")
+
+		var downloaded bool
+		s3.transfersCallback = func() ([]client.Transfer, error) {
+			return transfers, nil
+		}
+		s3.putObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) error {
+			return mockErr
+		}
+		s3.downloadFileCallback = func(bucket, key, path string, opts client.DownloadOptions) error {
+			downloaded = true
+			return nil
+		}
+
+		r := NewResume(&s3, nil, []string{})
+		if err := r.Execute(&out); err == nil {
+			t.Fatal("Expected error when a transfer fails")
+		} else if !downloaded {
+			t.Fatal("Expected the download to be resumed")
+		} else if output := strings.Join(out.output, ""); !strings.Contains(output, "Failed to resume: Upload: ") {
+			t.Fatalf("Expected output to contain the failure: %v", output)
+		}
+	}
+
+	// Interrupted
+	{
+		var s3 mockS3Client
+		var out mockOutputter
+
+		s3.transfersCallback = func() ([]client.Transfer, error) {
+			return transfers, nil
+		}
+		s3.putObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) error {
+			<-opts.Cancel
+			return client.ErrUploadInterrupted
+		}
+		s3.downloadFileCallback = func(bucket, key, path string, opts client.DownloadOptions) error {
+			t.Fatal("No further transfers should be resumed once interrupted")
+			return nil
+		}
+
+		r := NewResume(&s3, nil, []string{})
+		r.interrupt = make(chan os.Signal, 1)
+		r.interrupt <- os.Interrupt
+		if err := r.Execute(&out); err != client.ErrUploadInterrupted {
+			t.Fatalf("Unexpected error returned: %v", err)
+		}
+	}
+
+	// Invalid transfer numbers, and S3 error
+	for _, args := range [][]string{{"0"}, {"3"}, {"first"}, {}} {
+		var s3 mockS3Client
+		var out mockOutputter
+		mockErr := errors.New("Mock Error")
+
+		s3.transfersCallback = func() ([]client.Transfer, error) {
+			if len(args) == 0 {
+				return nil, mockErr
+			}
+			return transfers, nil
+		}
+
+		r := NewResume(&s3, nil, args)
+		if err := r.Execute(&out); err == nil {
+			t.Fatalf("Expected error for args: %v", args)
+		}
+	}
+}
+
+func TestResumeCommand_Execute_discard(t *testing.T) {
+	transfers := resumeTransfers("/tmp/backup.tar")
+
+	for _, confirmed := range []bool{true, false} {
+		var s3 mockS3Client
+		var out mockOutputter
+		in := mockInputter{lines: []string{"n"}}
+		if confirmed {
+			in.lines = []string{"y"}
+		}
+
+		var discarded []string
+		s3.transfersCallback = func() ([]client.Transfer, error) {
+			return transfers, nil
+		}
+		s3.discardTransferCallback = func(t client.Transfer) error {
+			discarded = append(discarded, t.Key)
+			return nil
+		}
+
+		r := NewResume(&s3, &in, []string{"--discard", "1"})
+		if err := r.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if !confirmed && len(discarded) > 0 {
+			t.Fatalf("Unexpected transfers discarded without confirmation: %v", discarded)
+		} else if confirmed && (len(discarded) != 1 || discarded[0] != "backup.tar") {
+			t.Fatalf("Unexpected transfers discarded: %v", discarded)
+		}
+	}
+}
+
+func TestResumeCommand_IsLongRunning(t *testing.T) {
+	if !NewResume(nil, nil, []string{}).IsLongRunning() {
+		t.Fatal("Expected ResumeCommand to be long running when resuming")
+	} else if NewResume(nil, nil, []string{"--list"}).IsLongRunning() {
+		t.Fatal("Expected ResumeCommand not to be long running when listing")
+	} else if NewResume(nil, nil, []string{"--discard"}).IsLongRunning() {
+		t.Fatal("Expected ResumeCommand not to be long running when confirmation is required")
+	}
+}
+
+func TestNewResume(t *testing.T) {
+	var s3 mockS3Client
+	var in mockInputter
+	args := []string{"1"}
+
+	r := NewResume(&s3, &in, args)
+	if r.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on resume command: %v", r.s3)
+	} else if r.in != &in {
+		t.Fatalf("Unexpected Inputter stored on resume command: %v", r.in)
+	} else if r.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on resume command: %v", r.args)
+	}
+}
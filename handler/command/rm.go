@@ -0,0 +1,197 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// rmFlagForce indicates that objects should be removed without confirmation.
+	rmFlagForce = "f"
+
+	// rmFlagRecursive indicates that folders, and every object within them, should be removed.
+	rmFlagRecursive = "r"
+
+	// rmFlagForceBucketRoot allows every object in a bucket to be removed recursively.
+	rmFlagForceBucketRoot = "force-bucket-root"
+)
+
+// RmCommand removes objects.
+type RmCommand struct {
+	s3  S3Client
+	con *context.Context
+	in  Inputter
+
+	args []string
+}
+
+// rmTarget is a resolved object or folder to be removed by an RmCommand.
+type rmTarget struct {
+	path     []string
+	isFolder bool
+}
+
+// Execute performs an 'rm' command by deleting each target object, after prompting the user for confirmation
+// unless forced.
+//
+// Folders are only removed when recursive, in which case every object within the folder is deleted in batches.
+func (rm RmCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(rm.args)
+	if len(flags.Args) == 0 {
+		return errors.New("Missing target file.")
+	}
+
+	// Resolve and validate every target before removing any of them.
+	targets := make([]rmTarget, len(flags.Args))
+	for i, arg := range flags.Args {
+		t, err := rm.resolve(arg, flags)
+		if err != nil {
+			return err
+		}
+		targets[i] = t
+	}
+
+	// Confirm the removal.
+	if !flags.Has(rmFlagForce) {
+		for _, t := range targets {
+			if t.isFolder {
+				out.Write("\n" + displayPath(t.path) + context.PathDelimiter + " (recursive)")
+			} else {
+				out.Write("\n" + displayPath(t.path))
+			}
+		}
+
+		if !confirm(out, rm.in, fmt.Sprintf("Remove %d object(s)?", len(targets))) {
+			out.Write("\nNothing removed.")
+			return nil
+		}
+	}
+
+	for _, t := range targets {
+		if !t.isFolder {
+			if err := rm.s3.DeleteObject(splitKeyPath(t.path)); err != nil {
+				return err
+			}
+
+			out.Write("\nRemoved: " + displayPath(t.path))
+			continue
+		}
+
+		if err := rm.removeFolder(out, t.path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolve calculates the object or folder to remove for a target argument, relative to the context, and
+// validates that it exists and may be removed.
+func (rm RmCommand) resolve(arg string, flags util.Flags) (rmTarget, error) {
+	p := rm.con.CalculatePath(arg)
+	bucket, key := splitKeyPath(p)
+
+	switch {
+
+	// The root can never be removed.
+	case len(p) == 0:
+		return rmTarget{}, fmt.Errorf("Target is not a file: %v", displayPath(p))
+
+	// Removing the root of a bucket is almost certainly a mistake, so it must be explicitly requested.
+	case len(key) == 0:
+		if !flags.Has(rmFlagRecursive) {
+			return rmTarget{}, fmt.Errorf("Target is a bucket, use -r to remove its contents: %v", displayPath(p))
+		} else if !flags.Has(rmFlagForceBucketRoot) {
+			return rmTarget{}, fmt.Errorf("Refusing to remove every object in %v without --%v", displayPath(p), rmFlagForceBucketRoot)
+		}
+
+		if ok, err := rm.s3.BucketExists(bucket); err != nil {
+			return rmTarget{}, err
+		} else if !ok {
+			return rmTarget{}, errors.New("No such bucket: " + displayPath(p))
+		}
+
+		return rmTarget{path: p, isFolder: true}, nil
+	}
+
+	// Check for a file before a folder.
+	if ok, err := rm.s3.ObjectExists(bucket, key); err != nil {
+		return rmTarget{}, err
+	} else if ok {
+		return rmTarget{path: p}, nil
+	}
+
+	if ok, err := rm.s3.PathExists(bucket, key+context.PathDelimiter); err != nil {
+		return rmTarget{}, err
+	} else if ok && !flags.Has(rmFlagRecursive) {
+		return rmTarget{}, fmt.Errorf("Target is a folder, use -r to remove it: %v", displayPath(p))
+	} else if ok {
+		return rmTarget{path: p, isFolder: true}, nil
+	}
+
+	return rmTarget{}, errors.New("No such file or directory: " + displayPath(p))
+}
+
+// removeFolder deletes every object within a folder, one page of the listing at a time, and outputs the running
+// count of removed objects along with any objects that could not be removed.
+func (rm RmCommand) removeFolder(out Outputter, p []string) error {
+	bucket, prefix := splitFolderPath(p)
+
+	var removed int
+	var failed []client.DeleteError
+	var deleteErr error
+
+	err := rm.s3.LsObjects(bucket, prefix, func(objects []client.Object) bool {
+		keys := make([]string, len(objects))
+		for i, o := range objects {
+			keys[i] = o.Key
+		}
+
+		f, err := rm.s3.DeleteObjects(bucket, keys)
+		if err != nil {
+			deleteErr = err
+			return false
+		}
+
+		removed += len(keys) - len(f)
+		failed = append(failed, f...)
+		out.Write(fmt.Sprintf("\nRemoved %d object(s) from %v...", removed, displayPath(p)))
+		return true
+	})
+	if err != nil {
+		return err
+	} else if deleteErr != nil {
+		return deleteErr
+	}
+
+	for _, f := range failed {
+		out.Write("\nFailed to remove: " + f.Error())
+	}
+
+	out.Write(fmt.Sprintf("\nRemoved: %v (%d object(s))", displayPath(p)+context.PathDelimiter, removed))
+	if len(failed) > 0 {
+		return fmt.Errorf("Failed to remove %d object(s) from %v", len(failed), displayPath(p))
+	}
+
+	return nil
+}
+
+// IsLongRunning returns true when the removal is forced, as the loading indicator would otherwise interfere
+// with the confirmation prompt.
+func (rm RmCommand) IsLongRunning() bool {
+	return util.ParseFlags(rm.args).Has(rmFlagForce)
+}
+
+// NewRm initializes and returns an RmCommand.
+func NewRm(s3 S3Client, con *context.Context, in Inputter, args []string) RmCommand {
+	return RmCommand{
+		s3:   s3,
+		con:  con,
+		in:   in,
+		args: args,
+	}
+}
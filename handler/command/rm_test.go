@@ -0,0 +1,278 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+func TestRmCommand_Execute(t *testing.T) {
+	// mockS3 returns a client containing the keys provided in 'bucket', and records deleted keys.
+	mockS3 := func(deleted *[]string, keys ...string) mockS3Client {
+		var s3 mockS3Client
+		s3.objectExistsCallback = func(bucket, key string) (bool, error) {
+			for _, k := range keys {
+				if bucket == "bucket" && key == k {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		s3.pathExistsCallback = func(bucket, prefix string) (bool, error) {
+			for _, k := range keys {
+				if bucket == "bucket" && strings.HasPrefix(k, prefix) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return bucket == "bucket", nil
+		}
+		s3.deleteObjectCallback = func(bucket, key string) error {
+			*deleted = append(*deleted, bucket+"/"+key)
+			return nil
+		}
+		return s3
+	}
+
+	// Confirmed
+	{
+		var deleted []string
+		s3 := mockS3(&deleted, "folder/a.txt", "b.txt")
+		var con context.Context
+		var out mockOutputter
+		in := mockInputter{lines: []string{"y"}}
+		con.UpdatePath("bucket")
+
+		rm := NewRm(&s3, &con, &in, []string{"folder/a.txt", "/bucket/b.txt"})
+		if err := rm.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(deleted) != 2 || deleted[0] != "bucket/folder/a.txt" || deleted[1] != "bucket/b.txt" {
+			t.Fatalf("Unexpected objects deleted: %v", deleted)
+		}
+
+		output := strings.Join(out.output, "")
+		if !strings.Contains(output, "\nRemove 2 object(s)? [y/N] ") {
+			t.Fatalf("Expected removal to be confirmed: %v", output)
+		} else if !strings.Contains(output, "Removed: /bucket/folder/a.txt") || !strings.Contains(output, "Removed: /bucket/b.txt") {
+			t.Fatalf("Expected removed keys to be output: %v", output)
+		}
+	}
+
+	// Refused
+	{
+		var deleted []string
+		s3 := mockS3(&deleted, "a.txt")
+		var con context.Context
+		var out mockOutputter
+		in := mockInputter{lines: []string{"n"}}
+		con.UpdatePath("bucket")
+
+		rm := NewRm(&s3, &con, &in, []string{"a.txt"})
+		if err := rm.Execute(&out); err != nil {
+			t.Fatal(err)
+		} else if len(deleted) != 0 {
+			t.Fatalf("Expected no objects to be deleted when refused: %v", deleted)
+		}
+	}
+
+	// Forced
+	{
+		var deleted []string
+		s3 := mockS3(&deleted, "a.txt")
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		rm := NewRm(&s3, &con, nil, []string{"-f", "a.txt"})
+		if err := rm.Execute(&out); err != nil {
+			t.Fatal(err)
+		} else if len(deleted) != 1 {
+			t.Fatalf("Expected object to be deleted without confirmation: %v", deleted)
+		}
+	}
+
+	// Invalid targets
+	{
+		for _, args := range [][]string{{}, {"-f"}, {"/"}, {"-r", "/"}, {"/bucket"}, {"-r", "/bucket"}, {"-r", "--force-bucket-root", "/fake"}, {"fake.txt"}, {"a.txt", "fake.txt"}, {"folder"}} {
+			var deleted []string
+			s3 := mockS3(&deleted, "a.txt", "folder/b.txt")
+			var con context.Context
+			var out mockOutputter
+			con.UpdatePath("bucket")
+
+			rm := NewRm(&s3, &con, nil, args)
+			if err := rm.Execute(&out); err == nil {
+				t.Fatalf("Expected error for invalid targets: %v", args)
+			} else if len(deleted) != 0 {
+				t.Fatalf("Expected no objects to be deleted for invalid targets: %v", deleted)
+			}
+		}
+	}
+
+	// S3 error
+	{
+		var deleted []string
+		s3 := mockS3(&deleted, "a.txt")
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		mockErr := errors.New("Mock Error")
+
+		s3.deleteObjectCallback = func(bucket, key string) error {
+			return mockErr
+		}
+
+		rm := NewRm(&s3, &con, nil, []string{"-f", "a.txt"})
+		if err := rm.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestRmCommand_Execute_recursive(t *testing.T) {
+	objects := []client.Object{{Key: "folder/a.txt"}, {Key: "folder/b.txt"}, {Key: "folder/sub/c.txt"}}
+
+	// mockS3 returns a client containing the objects in 'bucket', and records deleted keys.
+	mockS3 := func(deleted *[]string) mockS3Client {
+		var s3 mockS3Client
+		s3.objectExistsCallback = func(bucket, key string) (bool, error) {
+			return false, nil
+		}
+		s3.pathExistsCallback = func(bucket, prefix string) (bool, error) {
+			return bucket == "bucket" && prefix == "folder/", nil
+		}
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return bucket == "bucket", nil
+		}
+		s3.lsObjectsCallback = mockLsObjects(objects)
+		s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+			*deleted = append(*deleted, keys...)
+			return nil, nil
+		}
+		return s3
+	}
+
+	// Folder
+	{
+		var deleted []string
+		s3 := mockS3(&deleted)
+		var con context.Context
+		var out mockOutputter
+		in := mockInputter{lines: []string{"yes"}}
+		con.UpdatePath("bucket")
+
+		rm := NewRm(&s3, &con, &in, []string{"-r", "folder"})
+		if err := rm.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(deleted) != len(objects) {
+			t.Fatalf("Unexpected objects deleted: %v", deleted)
+		}
+
+		output := strings.Join(out.output, "")
+		if !strings.Contains(output, "Removed 3 object(s) from /bucket/folder...") || !strings.Contains(output, "Removed: /bucket/folder/ (3 object(s))") {
+			t.Fatalf("Expected progress and summary to be output: %v", output)
+		}
+	}
+
+	// Bucket root
+	{
+		var deleted []string
+		s3 := mockS3(&deleted)
+		var con context.Context
+		var out mockOutputter
+
+		rm := NewRm(&s3, &con, nil, []string{"-rf", "--force-bucket-root", "bucket"})
+		if err := rm.Execute(&out); err != nil {
+			t.Fatal(err)
+		} else if len(deleted) != len(objects) {
+			t.Fatalf("Unexpected objects deleted: %v", deleted)
+		}
+	}
+
+	// Partial failure
+	{
+		var deleted []string
+		s3 := mockS3(&deleted)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+			if keys[0] == "folder/b.txt" {
+				return []client.DeleteError{{Key: keys[0], Code: "AccessDenied", Message: "Access Denied"}}, nil
+			}
+			deleted = append(deleted, keys...)
+			return nil, nil
+		}
+
+		rm := NewRm(&s3, &con, nil, []string{"-rf", "folder"})
+		if err := rm.Execute(&out); err == nil {
+			t.Fatal("Expected error when objects fail to be removed")
+		} else if len(deleted) != 2 {
+			t.Fatalf("Expected remaining objects to be deleted after a failure: %v", deleted)
+		}
+
+		output := strings.Join(out.output, "")
+		if !strings.Contains(output, "Failed to remove: folder/b.txt") || !strings.Contains(output, "(2 object(s))") {
+			t.Fatalf("Expected failures to be output: %v", output)
+		}
+	}
+
+	// S3 error
+	{
+		var deleted []string
+		s3 := mockS3(&deleted)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		mockErr := errors.New("Mock Error")
+
+		var calls int
+		s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+			calls++
+			return nil, mockErr
+		}
+
+		rm := NewRm(&s3, &con, nil, []string{"-rf", "folder"})
+		if err := rm.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		} else if calls != 1 {
+			t.Fatalf("Expected listing to stop after an error: %v", calls)
+		}
+	}
+}
+
+func TestRmCommand_IsLongRunning(t *testing.T) {
+	if NewRm(nil, nil, nil, []string{"a.txt"}).IsLongRunning() {
+		t.Fatal("Expected RmCommand not to be long running when confirmation is required")
+	} else if !NewRm(nil, nil, nil, []string{"-f", "a.txt"}).IsLongRunning() {
+		t.Fatal("Expected RmCommand to be long running when forced")
+	}
+}
+
+func TestNewRm(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	var in mockInputter
+	args := []string{"file"}
+
+	rm := NewRm(&s3, &con, &in, args)
+	if rm.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on rm command: %v", rm.s3)
+	} else if rm.con != &con {
+		t.Fatalf("Unexpected Context stored on rm command: %v", rm.con)
+	} else if rm.in != &in {
+		t.Fatalf("Unexpected Inputter stored on rm command: %v", rm.in)
+	} else if rm.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on rm command: %v", rm.args)
+	}
+}
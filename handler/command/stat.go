@@ -0,0 +1,169 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// statTimeFormat is the format of timestamps output by 'stat'.
+	statTimeFormat = "2006-01-02 15:04:05 MST"
+)
+
+// StatCommand prints the metadata of objects and buckets.
+type StatCommand struct {
+	s3  S3Client
+	con *context.Context
+
+	args []string
+}
+
+// statField is a single labeled value output by a StatCommand.
+type statField struct {
+	label string
+	value string
+}
+
+// Execute performs a 'stat' command by outputting the full metadata of each target object, or the configuration
+// of each target bucket.
+func (stat StatCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(stat.args)
+	if len(flags.Args) == 0 {
+		return errors.New("Missing target file.")
+	}
+
+	for i, arg := range flags.Args {
+		var fields []statField
+		var err error
+
+		if p := stat.con.CalculatePath(arg); len(p) == 1 {
+			fields, err = stat.bucketFields(p)
+		} else {
+			fields, err = stat.objectFields(arg)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Separate each target with an empty line.
+		if i > 0 {
+			out.Write("\n")
+		}
+		writeFields(out, fields)
+	}
+
+	return nil
+}
+
+// objectFields returns the metadata of an object, omitting any optional fields that are not set.
+func (stat StatCommand) objectFields(arg string) ([]statField, error) {
+	p, info, err := headFile(stat.s3, stat.con, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []statField{
+		{"File", displayPath(p)},
+		{"Size", fmt.Sprintf("%d (%v)", info.Size, util.HumanSize(info.Size))},
+		{"Content-Type", info.ContentType},
+		{"ETag", info.ETag},
+		{"Last Modified", info.LastModified.Local().Format(statTimeFormat)},
+		{"Storage Class", info.StorageClass},
+	}
+
+	if len(info.ServerSideEncryption) > 0 {
+		fields = append(fields, statField{"Encryption", encryptionDescription(info.ServerSideEncryption, info.KMSKeyID)})
+	}
+	if len(info.VersionID) > 0 {
+		fields = append(fields, statField{"Version", info.VersionID})
+	}
+	if len(info.Restore) > 0 {
+		fields = append(fields, statField{"Restore", info.Restore})
+	}
+
+	// User metadata is output in order of name.
+	names := make([]string, 0, len(info.Metadata))
+	for name := range info.Metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fields = append(fields, statField{"x-amz-meta-" + name, info.Metadata[name]})
+	}
+
+	return fields, nil
+}
+
+// bucketFields returns the region, versioning and default encryption configuration of a bucket.
+func (stat StatCommand) bucketFields(p []string) ([]statField, error) {
+	if ok, err := stat.s3.BucketExists(p[0]); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.New("No such bucket: " + displayPath(p))
+	}
+
+	info, err := stat.s3.GetBucketInfo(p[0])
+	if err != nil {
+		return nil, err
+	}
+
+	// Versioning has no status until it is enabled for the first time.
+	versioning := info.Versioning
+	if len(versioning) == 0 {
+		versioning = "Disabled"
+	}
+
+	encryption := "None"
+	if len(info.Encryption) > 0 {
+		encryption = encryptionDescription(info.Encryption, info.KMSKeyID)
+	}
+
+	return []statField{
+		{"Bucket", displayPath(p)},
+		{"Region", info.Region},
+		{"Versioning", versioning},
+		{"Encryption", encryption},
+	}, nil
+}
+
+// IsLongRunning returns true because 'stat' requires network operations.
+func (StatCommand) IsLongRunning() bool {
+	return true
+}
+
+// NewStat initializes and returns a StatCommand.
+func NewStat(s3 S3Client, con *context.Context, args []string) StatCommand {
+	return StatCommand{
+		s3:   s3,
+		con:  con,
+		args: args,
+	}
+}
+
+// encryptionDescription returns a human-readable description of a server-side encryption algorithm, including
+// the KMS key used, if any.
+func encryptionDescription(algorithm, kmsKeyID string) string {
+	if len(kmsKeyID) > 0 {
+		return algorithm + " (" + kmsKeyID + ")"
+	}
+
+	return algorithm
+}
+
+// writeFields outputs one labeled field per line, with the values aligned.
+func writeFields(out Outputter, fields []statField) {
+	var width int
+	for _, f := range fields {
+		if len(f.label) > width {
+			width = len(f.label)
+		}
+	}
+
+	for _, f := range fields {
+		out.Write(fmt.Sprintf("\n%-*s %v", width+1, f.label+":", f.value))
+	}
+}
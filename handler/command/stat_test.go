@@ -0,0 +1,197 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+func TestStatCommand_Execute(t *testing.T) {
+	modified := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "a.txt"}, {Key: "archive/b.txt"}},
+	}
+	infos := map[string]client.ObjectInfo{
+		"a.txt": {
+			Key:          "a.txt",
+			Size:         1536,
+			ContentType:  "text/plain",
+			ETag:         "\"etag\"",
+			LastModified: modified,
+			StorageClass: "STANDARD",
+		},
+		"archive/b.txt": {
+			Key:                  "archive/b.txt",
+			Size:                 10,
+			ContentType:          "text/plain",
+			ETag:                 "\"etag2\"",
+			LastModified:         modified,
+			StorageClass:         "GLACIER",
+			ServerSideEncryption: "aws:kms",
+			KMSKeyID:             "key-id",
+			VersionID:            "version",
+			Restore:              "ongoing-request=\"true\"",
+			Metadata:             map[string]string{"owner": "kyle", "author": "kyle"},
+		},
+	}
+	bucketInfo := client.BucketInfo{Name: "bucket", Region: "eu-west-1", Encryption: "AES256"}
+	lastModified := modified.Local().Format(statTimeFormat)
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"a.txt"}, []string{
+			"\nFile:          /bucket/a.txt",
+			"\nSize:          1536 (1.5K)",
+			"\nContent-Type:  text/plain",
+			"\nETag:          \"etag\"",
+			"\nLast Modified: " + lastModified,
+			"\nStorage Class: STANDARD",
+		}},
+		{[]string{"archive/b.txt"}, []string{
+			"\nFile:              /bucket/archive/b.txt",
+			"\nSize:              10 (10)",
+			"\nContent-Type:      text/plain",
+			"\nETag:              \"etag2\"",
+			"\nLast Modified:     " + lastModified,
+			"\nStorage Class:     GLACIER",
+			"\nEncryption:        aws:kms (key-id)",
+			"\nVersion:           version",
+			"\nRestore:           ongoing-request=\"true\"",
+			"\nx-amz-meta-author: kyle",
+			"\nx-amz-meta-owner:  kyle",
+		}},
+		{[]string{"/bucket"}, []string{
+			"\nBucket:     /bucket",
+			"\nRegion:     eu-west-1",
+			"\nVersioning: Disabled",
+			"\nEncryption: AES256",
+		}},
+		{[]string{".", "a.txt"}, []string{
+			"\nBucket:     /bucket",
+			"\nRegion:     eu-west-1",
+			"\nVersioning: Disabled",
+			"\nEncryption: AES256",
+			"\n",
+			"\nFile:          /bucket/a.txt",
+			"\nSize:          1536 (1.5K)",
+			"\nContent-Type:  text/plain",
+			"\nETag:          \"etag\"",
+			"\nLast Modified: " + lastModified,
+			"\nStorage Class: STANDARD",
+		}},
+	}
+
+	for _, test := range tests {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.headObjectCallback = func(bucket, key string) (client.ObjectInfo, error) {
+			return infos[key], nil
+		}
+		s3.bucketInfoCallback = func(bucket string) (client.BucketInfo, error) {
+			return bucketInfo, nil
+		}
+
+		stat := NewStat(&s3, &con, test.args)
+		if err := stat.Execute(&out); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.args, err)
+		}
+
+		expected := strings.Join(test.expected, "")
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected output for %v: {Expected: %q, Actual: %q}", test.args, expected, output)
+		}
+	}
+}
+
+func TestStatCommand_Execute_invalid(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "a.txt"}, {Key: "folder/b.txt"}},
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"/"},
+		{"/fake"},
+		{"folder"},
+		{"fake.txt"},
+	} {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.objectExistsCallback = func(bucket, key string) (bool, error) {
+			t.Fatalf("ObjectExists should not be called, as HeadObject determines whether the object exists: %v", args)
+			return false, nil
+		}
+		s3.headObjectCallback = func(bucket, key string) (client.ObjectInfo, error) {
+			return client.ObjectInfo{}, client.ErrObjectNotFound
+		}
+		s3.bucketInfoCallback = func(bucket string) (client.BucketInfo, error) {
+			t.Fatalf("GetBucketInfo should not be called for invalid args: %v", args)
+			return client.BucketInfo{}, nil
+		}
+
+		stat := NewStat(&s3, &con, args)
+		if err := stat.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		} else if len(args) == 1 && args[0] == "fake.txt" && err.Error() != "No such file or directory: /bucket/fake.txt" {
+			t.Fatalf("Unexpected error for a missing file: %v", err)
+		}
+	}
+
+	// S3 errors
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		mockErr := errors.New("Mock Error")
+
+		s3.headObjectCallback = func(bucket, key string) (client.ObjectInfo, error) {
+			return client.ObjectInfo{}, mockErr
+		}
+		s3.bucketInfoCallback = func(bucket string) (client.BucketInfo, error) {
+			return client.BucketInfo{}, mockErr
+		}
+
+		for _, args := range [][]string{{"a.txt"}, {"/bucket"}} {
+			stat := NewStat(&s3, &con, args)
+			if err := stat.Execute(&out); err != mockErr {
+				t.Fatalf("Expected error to be passed up the stack: %v", err)
+			}
+		}
+	}
+}
+
+func TestStatCommand_IsLongRunning(t *testing.T) {
+	stat := NewStat(nil, nil, nil)
+
+	if !stat.IsLongRunning() {
+		t.Fatal("Expected StatCommand to always be long running")
+	}
+}
+
+func TestNewStat(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	args := []string{"a.txt"}
+
+	stat := NewStat(&s3, &con, args)
+	if stat.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on stat command: %v", stat.s3)
+	} else if stat.con != &con {
+		t.Fatalf("Unexpected Context stored on stat command: %v", stat.con)
+	} else if stat.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on stat command: %v", stat.args)
+	}
+}
@@ -0,0 +1,610 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// syncArgsIndexSource indicates the expected argument index for the source to sync from.
+	syncArgsIndexSource = 0
+
+	// syncArgsIndexDestination indicates the expected argument index for the destination to sync to.
+	syncArgsIndexDestination = 1
+
+	// syncFlagDelete indicates that files at the destination which do not exist in the source should be removed.
+	syncFlagDelete = "delete"
+
+	// syncFlagForceBucketRoot allows files to be removed from the root of a bucket with --delete.
+	syncFlagForceBucketRoot = "force-bucket-root"
+
+	// syncFlagDryRun indicates that the changes should be printed, without being performed.
+	syncFlagDryRun = "dry-run"
+
+	// syncFlagConcurrency provides the number of files transferred or removed at once.
+	syncFlagConcurrency = "concurrency"
+
+	// defaultSyncConcurrency is the number of files transferred or removed at once, unless otherwise provided.
+	defaultSyncConcurrency = 8
+
+	// localPathPrefixes are the prefixes that distinguish a local path from an Amazon S3 path.
+	localPathPrefixes = ".~"
+
+	// syncSchemeLocal explicitly marks a local path, such as 'file:///home/user/dist'.
+	syncSchemeLocal = "file://"
+
+	// syncSchemeS3 explicitly marks an absolute Amazon S3 path, such as 's3://bucket/site'.
+	syncSchemeS3 = "s3://"
+)
+
+// errSyncInterrupted is returned when a sync is interrupted before every file has been transferred.
+var errSyncInterrupted = errors.New("Sync interrupted, run the same sync again to resume it")
+
+// SyncCommand mirrors a local directory to an Amazon S3 folder, an Amazon S3 folder to a local directory, or one
+// Amazon S3 folder to another, transferring only the files that have changed.
+type SyncCommand struct {
+	s3  S3Client
+	con *context.Context
+
+	args []string
+
+	interrupt chan os.Signal
+}
+
+// syncLocation is the source or destination of a sync, which is either a local directory or an Amazon S3 folder.
+type syncLocation struct {
+	dir string
+
+	bucket string
+	prefix string
+}
+
+// syncEntry is a file within a syncLocation, keyed by its path relative to the location.
+type syncEntry struct {
+	size    int64
+	modTime time.Time
+
+	// path is the absolute path of a local file, and etag is the ETag of an object.
+	path string
+	etag string
+}
+
+// syncAction is a single transfer or removal to be performed by a sync.
+type syncAction struct {
+	description string
+	size        int64
+	isDelete    bool
+	run         func() error
+
+	// key is the object removed by a removal from Amazon S3, which is performed in a batch rather than run.
+	key string
+}
+
+// Execute performs a 'sync' by comparing the files in the source and destination, and transferring each that is
+// missing or has changed at the destination.
+//
+// Files are compared by size and, when the source is newer than the destination, by checksum where possible.
+// Files are copied between Amazon S3 folders by Amazon S3 itself, so no data is downloaded.
+func (s SyncCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(s.args, syncFlagConcurrency)
+	if len(flags.Args) < syncArgsIndexDestination+1 {
+		return errors.New("Missing source or destination.")
+	}
+
+	concurrency := defaultSyncConcurrency
+	if flags.Has(syncFlagConcurrency) {
+		n, err := strconv.Atoi(flags.Value(syncFlagConcurrency))
+		if err != nil || n < 1 {
+			return fmt.Errorf("Invalid concurrency: %v", flags.Value(syncFlagConcurrency))
+		}
+		concurrency = n
+	}
+
+	src, err := s.location(flags.Args[syncArgsIndexSource])
+	if err != nil {
+		return err
+	}
+	dst, err := s.location(flags.Args[syncArgsIndexDestination])
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case src.isLocal() && dst.isLocal():
+		return errors.New("The source or destination must be in Amazon S3.")
+	case src.isLocal() || dst.isLocal() || src.bucket != dst.bucket:
+	case src.prefix == dst.prefix:
+		return fmt.Errorf("Source and destination are the same: %v", src)
+	case strings.HasPrefix(dst.prefix, src.prefix), strings.HasPrefix(src.prefix, dst.prefix):
+		return fmt.Errorf("Cannot sync a folder with a folder inside of it: %v, %v", src, dst)
+	}
+
+	// Removing every other object in a bucket is almost certainly a mistake, so it must be explicitly requested.
+	if flags.Has(syncFlagDelete) && !dst.isLocal() && len(dst.prefix) == 0 && !flags.Has(syncFlagForceBucketRoot) {
+		return fmt.Errorf("Refusing to remove objects from the root of %v without --%v", dst, syncFlagForceBucketRoot)
+	}
+
+	if err := s.validate(src, dst); err != nil {
+		return err
+	}
+
+	srcEntries, err := s.list(src)
+	if err != nil {
+		return err
+	}
+	dstEntries, err := s.list(dst)
+	if err != nil {
+		return err
+	}
+
+	cancel, stop := cancelOnInterrupt(s.interrupt)
+	defer stop()
+
+	actions, skipped, err := s.plan(src, dst, srcEntries, dstEntries, flags.Has(syncFlagDelete), cancel)
+	if err != nil {
+		return err
+	}
+
+	if flags.Has(syncFlagDryRun) {
+		var transfers, deletes int
+		for _, a := range actions {
+			out.Write("\n(dry run) " + a.description)
+			if a.isDelete {
+				deletes++
+			} else {
+				transfers++
+			}
+		}
+
+		out.Write(fmt.Sprintf("\nWould transfer %d file(s), skip %d unchanged file(s), and remove %d file(s)", transfers, skipped, deletes))
+		return nil
+	}
+
+	return s.run(out, dst, actions, skipped, concurrency, cancel)
+}
+
+// location parses a sync argument as a local directory or an Amazon S3 folder.
+//
+// Local paths are marked by the 'file://' scheme, or by beginning with '.' or '~', such as './dist', '../dist' or
+// '~/dist'. Any other argument is an Amazon S3 path, relative to the context unless marked by the 's3://' scheme.
+// An unmarked argument that is also a local directory is refused, as the intended location is ambiguous.
+func (s SyncCommand) location(arg string) (syncLocation, error) {
+	switch {
+	case strings.HasPrefix(arg, syncSchemeLocal):
+		return localSyncLocation(strings.TrimPrefix(arg, syncSchemeLocal))
+	case len(arg) > 0 && strings.ContainsAny(arg[:1], localPathPrefixes):
+		return localSyncLocation(arg)
+	case strings.HasPrefix(arg, syncSchemeS3):
+		arg = context.PathDelimiter + strings.TrimPrefix(arg, syncSchemeS3)
+	default:
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			return syncLocation{}, fmt.Errorf("Ambiguous path, use %v or %v to sync a local directory or Amazon S3 folder: %v",
+				syncSchemeLocal+arg, syncSchemeS3+strings.TrimPrefix(arg, context.PathDelimiter), arg)
+		}
+	}
+
+	p := s.con.CalculatePath(arg)
+	if len(p) == 0 {
+		return syncLocation{}, errors.New("Cannot sync the root.")
+	}
+
+	bucket, prefix := splitFolderPath(p)
+	return syncLocation{bucket: bucket, prefix: prefix}, nil
+}
+
+// localSyncLocation returns the local directory at path.
+func localSyncLocation(path string) (syncLocation, error) {
+	dir, err := util.AbsPath(path)
+	if err != nil {
+		return syncLocation{}, err
+	}
+
+	return syncLocation{dir: dir}, nil
+}
+
+// validate ensures the source exists, and that the destination can be written to.
+func (s SyncCommand) validate(src, dst syncLocation) error {
+	if src.isLocal() {
+		if info, err := os.Stat(src.dir); err != nil {
+			return err
+		} else if !info.IsDir() {
+			return errors.New("Source is not a directory: " + src.dir)
+		}
+	} else if ok, err := folderExists(s.s3, src.bucket, src.prefix); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("No such file or directory: " + src.String())
+	}
+
+	if dst.isLocal() {
+		if info, _ := os.Stat(dst.dir); info != nil && !info.IsDir() {
+			return errors.New("Destination is not a directory: " + dst.dir)
+		}
+	} else if ok, err := s.s3.BucketExists(dst.bucket); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("No such bucket: " + displayPath([]string{dst.bucket}))
+	}
+
+	return nil
+}
+
+// list returns every file within a location, keyed by its slash separated path relative to the location.
+//
+// Folder marker objects are ignored, and a local directory that does not exist is empty.
+func (s SyncCommand) list(loc syncLocation) (map[string]syncEntry, error) {
+	entries := make(map[string]syncEntry)
+
+	if !loc.isLocal() {
+		err := s.s3.LsObjects(loc.bucket, loc.prefix, func(objects []client.Object) bool {
+			for _, o := range objects {
+				if strings.HasSuffix(o.Key, context.PathDelimiter) {
+					continue
+				}
+
+				entries[strings.TrimPrefix(o.Key, loc.prefix)] = syncEntry{
+					size:    o.Size,
+					modTime: o.LastModified,
+					etag:    o.ETag,
+				}
+			}
+			return true
+		})
+
+		return entries, err
+	}
+
+	if _, err := os.Stat(loc.dir); os.IsNotExist(err) {
+		return entries, nil
+	}
+
+	err := filepath.Walk(loc.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(loc.dir, path)
+		if err != nil {
+			return err
+		}
+
+		entries[filepath.ToSlash(rel)] = syncEntry{
+			size:    info.Size(),
+			modTime: info.ModTime(),
+			path:    path,
+		}
+		return nil
+	})
+
+	return entries, err
+}
+
+// plan determines the actions required to bring the destination in line with the source, in order of their
+// relative paths, along with the number of files that are unchanged. Transfers stop once cancel is closed.
+func (s SyncCommand) plan(src, dst syncLocation, srcEntries, dstEntries map[string]syncEntry, del bool, cancel <-chan struct{}) ([]syncAction, int, error) {
+	var actions []syncAction
+	var skipped int
+
+	for _, rel := range sortedEntryKeys(srcEntries) {
+		e := srcEntries[rel]
+		if existing, ok := dstEntries[rel]; ok {
+			if changed, err := e.changed(existing); err != nil {
+				return nil, 0, err
+			} else if !changed {
+				skipped++
+				continue
+			}
+		}
+
+		actions = append(actions, s.transfer(src, dst, rel, e, cancel))
+	}
+
+	if del {
+		for _, rel := range sortedEntryKeys(dstEntries) {
+			if _, ok := srcEntries[rel]; !ok {
+				actions = append(actions, s.remove(dst, rel))
+			}
+		}
+	}
+
+	return actions, skipped, nil
+}
+
+// transfer returns the action that copies a file from the source to the destination, which stops once cancel is
+// closed. Copies between Amazon S3 folders are performed by Amazon S3 itself, and can't be stopped.
+func (s SyncCommand) transfer(src, dst syncLocation, rel string, e syncEntry, cancel <-chan struct{}) syncAction {
+	a := syncAction{
+		description: src.path(rel) + " -> " + dst.path(rel),
+		size:        e.size,
+	}
+
+	switch {
+	case !src.isLocal() && !dst.isLocal():
+		a.description = "Copy: " + a.description
+		a.run = func() error {
+			return s.s3.CopyObject(src.bucket, src.prefix+rel, dst.bucket, dst.prefix+rel)
+		}
+		return a
+
+	case src.isLocal():
+		a.description = "Upload: " + a.description
+		a.run = func() error {
+			f, err := os.Open(e.path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			return s.s3.PutObject(dst.bucket, dst.prefix+rel, f, client.UploadOptions{Cancel: cancel})
+		}
+		return a
+	}
+
+	a.description = "Download: " + a.description
+	a.run = func() error {
+		local, err := localKeyPath(dst.dir, rel)
+		if err != nil {
+			return err
+		} else if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+			return err
+		} else if err := s.s3.DownloadFile(src.bucket, src.prefix+rel, local, client.DownloadOptions{Cancel: cancel}); err != nil {
+			return err
+		}
+
+		// Match the modification time of the object, so the file is known to be unchanged by future syncs.
+		return os.Chtimes(local, e.modTime, e.modTime)
+	}
+	return a
+}
+
+// remove returns the action that removes a file from the destination.
+func (s SyncCommand) remove(dst syncLocation, rel string) syncAction {
+	a := syncAction{
+		description: "Remove: " + dst.path(rel),
+		isDelete:    true,
+	}
+
+	if dst.isLocal() {
+		a.run = func() error {
+			return os.Remove(dst.path(rel))
+		}
+		return a
+	}
+
+	a.key = dst.prefix + rel
+	return a
+}
+
+// IsLongRunning returns true because 'sync' must always perform network requests.
+func (SyncCommand) IsLongRunning() bool {
+	return true
+}
+
+// NewSync initializes and returns a SyncCommand.
+func NewSync(s3 S3Client, con *context.Context, args []string) SyncCommand {
+	return SyncCommand{
+		s3:   s3,
+		con:  con,
+		args: args,
+	}
+}
+
+// isLocal returns true if the location is a local directory.
+func (l syncLocation) isLocal() bool {
+	return len(l.dir) > 0
+}
+
+// path returns the display path of a file within the location.
+func (l syncLocation) path(rel string) string {
+	if l.isLocal() {
+		return filepath.Join(l.dir, filepath.FromSlash(rel))
+	}
+
+	return displayPath([]string{l.bucket, l.prefix + rel})
+}
+
+// String returns the display path of the location itself.
+func (l syncLocation) String() string {
+	if l.isLocal() {
+		return l.dir
+	}
+
+	return displayPath([]string{l.bucket, l.prefix})
+}
+
+// changed returns true if the entry differs from an existing entry at the destination.
+//
+// Files of the same size are only compared by checksum if the entry is newer than the existing entry, and are
+// considered changed if either checksum is unavailable.
+func (e syncEntry) changed(existing syncEntry) (bool, error) {
+	if e.size != existing.size {
+		return true, nil
+	} else if !e.modTime.After(existing.modTime) {
+		return false, nil
+	}
+
+	a, err := e.checksum()
+	if err != nil {
+		return false, err
+	}
+	b, err := existing.checksum()
+	if err != nil {
+		return false, err
+	}
+
+	return len(a) == 0 || len(b) == 0 || a != b, nil
+}
+
+// checksum returns the MD5 checksum of the entry's contents, or an empty string if it is unknown, as is the case
+// for objects uploaded in multiple parts.
+func (e syncEntry) checksum() (string, error) {
+	if len(e.path) > 0 {
+		return fileMD5(e.path)
+	}
+
+	etag := strings.Trim(e.etag, `"`)
+	if strings.Contains(etag, multipartETagSeparator) {
+		return "", nil
+	}
+
+	return etag, nil
+}
+
+// run performs up to n transfers at once, outputting each as it completes followed by a summary.
+//
+// Removals are only performed once every transfer has completed, and objects are removed from Amazon S3 in batches.
+// Once cancelled, no further transfers are started and nothing is removed.
+func (s SyncCommand) run(out Outputter, dst syncLocation, actions []syncAction, skipped, n int, cancel <-chan struct{}) error {
+	var transfers, deletes []syncAction
+	for _, a := range actions {
+		if a.isDelete {
+			deletes = append(deletes, a)
+		} else {
+			transfers = append(transfers, a)
+		}
+	}
+
+	var failed, transferred, removed int
+	var size int64
+	for r := range runConcurrently(transfers, n, cancel) {
+		switch {
+		case r.err == client.ErrUploadInterrupted || r.err == client.ErrDownloadInterrupted:
+		case r.err != nil:
+			failed++
+			out.Write(fmt.Sprintf("\nFailed: %v: %v", r.action.description, r.err))
+		default:
+			transferred++
+			size += r.action.size
+			out.Write("\n" + r.action.description)
+		}
+	}
+
+	switch {
+	case isCancelled(cancel), len(deletes) == 0:
+
+	case dst.isLocal():
+		for r := range runConcurrently(deletes, n, cancel) {
+			if r.err != nil {
+				failed++
+				out.Write(fmt.Sprintf("\nFailed: %v: %v", r.action.description, r.err))
+				continue
+			}
+
+			removed++
+			out.Write("\n" + r.action.description)
+		}
+
+	default:
+		r, f, err := s.removeObjects(out, dst, deletes)
+		if err != nil {
+			return err
+		}
+		removed += r
+		failed += f
+	}
+
+	out.Write(fmt.Sprintf("\nTransferred %d file(s) (%v), skipped %d unchanged file(s), and removed %d file(s)",
+		transferred, util.HumanSize(size), skipped, removed))
+
+	if isCancelled(cancel) {
+		return errSyncInterrupted
+	} else if failed > 0 {
+		return fmt.Errorf("Failed to sync %d file(s)", failed)
+	}
+
+	return nil
+}
+
+// removeObjects removes the objects of removal actions from an Amazon S3 destination in batches, and outputs each
+// object removed along with any objects that could not be removed. The number of objects removed and not removed
+// are returned.
+func (s SyncCommand) removeObjects(out Outputter, dst syncLocation, deletes []syncAction) (int, int, error) {
+	keys := make([]string, len(deletes))
+	for i, a := range deletes {
+		keys[i] = a.key
+	}
+
+	failed, err := s.s3.DeleteObjects(dst.bucket, keys)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	notRemoved := make(map[string]bool, len(failed))
+	for _, f := range failed {
+		notRemoved[f.Key] = true
+		out.Write("\nFailed to remove: " + f.Error())
+	}
+	for _, a := range deletes {
+		if !notRemoved[a.key] {
+			out.Write("\n" + a.description)
+		}
+	}
+
+	return len(keys) - len(failed), len(failed), nil
+}
+
+// syncResult is the outcome of a syncAction.
+type syncResult struct {
+	action syncAction
+	err    error
+}
+
+// runConcurrently performs actions using up to n goroutines, providing the result of each on the returned channel,
+// which is closed once every action has completed. No further actions are started once cancel is closed.
+func runConcurrently(actions []syncAction, n int, cancel <-chan struct{}) <-chan syncResult {
+	queue := make(chan syncAction)
+	results := make(chan syncResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range queue {
+				results <- syncResult{action: a, err: a.run()}
+			}
+		}()
+	}
+
+	go func() {
+	enqueue:
+		for _, a := range actions {
+			// Stop queueing actions once cancelled.
+			select {
+			case <-cancel:
+				break enqueue
+			case queue <- a:
+			}
+		}
+
+		close(queue)
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// sortedEntryKeys returns the relative paths of entries in sorted order.
+func sortedEntryKeys(entries map[string]syncEntry) []string {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,590 @@
+package command
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+// syncETag returns the ETag of an object with the contents provided.
+func syncETag(contents string) string {
+	sum := md5.Sum([]byte(contents))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// writeSyncFiles creates local files with the contents provided, relative to dir.
+func writeSyncFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		} else if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// mockSyncS3 returns a client listing the objects provided, recording each key that is uploaded, downloaded or
+// deleted. Downloaded objects contain their bucket and key.
+func mockSyncS3(buckets map[string][]client.Object, calls *[]string) mockS3Client {
+	var mu sync.Mutex
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+		*calls = append(*calls, call)
+	}
+
+	s3 := newMockS3Listing(buckets)
+	s3.putObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) error {
+		record("put " + bucket + "/" + key)
+		return nil
+	}
+	s3.downloadFileCallback = func(bucket, key, path string, opts client.DownloadOptions) error {
+		record("get " + bucket + "/" + key)
+
+		return ioutil.WriteFile(path, []byte(bucket+"/"+key), 0644)
+	}
+	s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+		for _, key := range keys {
+			record("delete " + bucket + "/" + key)
+		}
+		return nil, nil
+	}
+	s3.copyObjectCallback = func(srcBucket, srcKey, dstBucket, dstKey string) error {
+		record("copy " + srcBucket + "/" + srcKey + " " + dstBucket + "/" + dstKey)
+		return nil
+	}
+	return s3
+}
+
+func TestSyncCommand_Execute_upload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeSyncFiles(t, dir, map[string]string{
+		"new.txt":         "new",
+		"unchanged.txt":   "unchanged",
+		"touched.txt":     "touched",
+		"modified.txt":    "modified",
+		"resized.txt":     "resized!",
+		"sub/nested.txt":  "nested",
+		"sub/multi.txt":   "multi",
+		"sub/current.txt": "current",
+	})
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	buckets := map[string][]client.Object{
+		"bucket": {
+			{Key: "site/"},
+			{Key: "site/unchanged.txt", Size: 9, ETag: syncETag("unchanged"), LastModified: future},
+			{Key: "site/touched.txt", Size: 7, ETag: syncETag("touched"), LastModified: past},
+			{Key: "site/modified.txt", Size: 8, ETag: syncETag("MODIFIED"), LastModified: past},
+			{Key: "site/resized.txt", Size: 7, ETag: syncETag("resized"), LastModified: future},
+			{Key: "site/sub/multi.txt", Size: 5, ETag: `"abc-2"`, LastModified: past},
+			{Key: "site/sub/current.txt", Size: 7, ETag: `"abc-2"`, LastModified: future},
+			{Key: "site/extra.txt", Size: 5, LastModified: past},
+		},
+	}
+
+	tests := []struct {
+		args   []string
+		calls  []string
+		output string
+	}{
+		{[]string{"./" + filepath.Base(dir), "site"}, []string{
+			"put bucket/site/modified.txt",
+			"put bucket/site/new.txt",
+			"put bucket/site/resized.txt",
+			"put bucket/site/sub/multi.txt",
+			"put bucket/site/sub/nested.txt",
+		}, "\nTransferred 5 file(s) (30), skipped 3 unchanged file(s), and removed 0 file(s)"},
+		{[]string{"--delete", "./" + filepath.Base(dir), "/bucket/site/"}, []string{
+			"delete bucket/site/extra.txt",
+			"put bucket/site/modified.txt",
+			"put bucket/site/new.txt",
+			"put bucket/site/resized.txt",
+			"put bucket/site/sub/multi.txt",
+			"put bucket/site/sub/nested.txt",
+		}, "\nTransferred 5 file(s) (30), skipped 3 unchanged file(s), and removed 1 file(s)"},
+		{[]string{"file://" + dir, "s3://bucket/site"}, []string{
+			"put bucket/site/modified.txt",
+			"put bucket/site/new.txt",
+			"put bucket/site/resized.txt",
+			"put bucket/site/sub/multi.txt",
+			"put bucket/site/sub/nested.txt",
+		}, "\nTransferred 5 file(s) (30), skipped 3 unchanged file(s), and removed 0 file(s)"},
+	}
+
+	// Relative local paths are resolved from the working directory.
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(filepath.Dir(dir))
+
+	for _, test := range tests {
+		var calls []string
+		s3 := mockSyncS3(buckets, &calls)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		sync := NewSync(&s3, &con, test.args)
+		if err := sync.Execute(&out); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.args, err)
+		}
+
+		sort.Strings(calls)
+		if !reflect.DeepEqual(calls, test.calls) {
+			t.Fatalf("Unexpected calls for %v: {Expected: %v, Actual: %v}", test.args, test.calls, calls)
+		}
+
+		output := strings.Join(out.output, "")
+		if !strings.HasSuffix(output, test.output) {
+			t.Fatalf("Unexpected output for %v: {Expected: %q, Actual: %q}", test.args, test.output, output)
+		} else if !strings.Contains(output, "\nUpload: "+filepath.Join(dir, "new.txt")+" -> /bucket/site/new.txt") {
+			t.Fatalf("Expected each transfer to be output for %v: %q", test.args, output)
+		}
+	}
+
+	// Dry run
+	{
+		var calls []string
+		s3 := mockSyncS3(buckets, &calls)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		sync := NewSync(&s3, &con, []string{"--dry-run", "--delete", "./" + filepath.Base(dir), "/bucket/site"})
+		if err := sync.Execute(&out); err != nil {
+			t.Fatal(err)
+		} else if len(calls) > 0 {
+			t.Fatalf("Unexpected calls during dry run: %v", calls)
+		}
+
+		expected := strings.Join([]string{
+			"\n(dry run) Upload: " + filepath.Join(dir, "modified.txt") + " -> /bucket/site/modified.txt",
+			"\n(dry run) Upload: " + filepath.Join(dir, "new.txt") + " -> /bucket/site/new.txt",
+			"\n(dry run) Upload: " + filepath.Join(dir, "resized.txt") + " -> /bucket/site/resized.txt",
+			"\n(dry run) Upload: " + filepath.Join(dir, "sub", "multi.txt") + " -> /bucket/site/sub/multi.txt",
+			"\n(dry run) Upload: " + filepath.Join(dir, "sub", "nested.txt") + " -> /bucket/site/sub/nested.txt",
+			"\n(dry run) Remove: /bucket/site/extra.txt",
+			"\nWould transfer 5 file(s), skip 3 unchanged file(s), and remove 1 file(s)",
+		}, "")
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+}
+
+func TestSyncCommand_Execute_download(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeSyncFiles(t, dir, map[string]string{
+		"unchanged.txt": "bucket/site/unchanged.txt",
+		"extra.txt":     "extra",
+	})
+
+	modified := time.Now().Add(-time.Hour).Truncate(time.Second)
+	buckets := map[string][]client.Object{
+		"bucket": {
+			{Key: "site/a.txt", Size: 15, LastModified: modified},
+			{Key: "site/sub/b.txt", Size: 19, LastModified: modified},
+			{Key: "site/unchanged.txt", Size: 25, ETag: syncETag("bucket/site/unchanged.txt"), LastModified: time.Now().Add(time.Hour)},
+		},
+	}
+
+	var calls []string
+	s3 := mockSyncS3(buckets, &calls)
+	var con context.Context
+	var out mockOutputter
+	con.UpdatePath("bucket/site")
+
+	// Relative local paths are resolved from the working directory.
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(filepath.Dir(dir))
+
+	sync := NewSync(&s3, &con, []string{"/bucket/site", "./" + filepath.Base(dir), "--delete"})
+	if err := sync.Execute(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(calls)
+	expected := []string{"get bucket/site/a.txt", "get bucket/site/sub/b.txt"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("Unexpected calls: {Expected: %v, Actual: %v}", expected, calls)
+	}
+
+	for name, contents := range map[string]string{
+		"a.txt":         "bucket/site/a.txt",
+		"sub/b.txt":     "bucket/site/sub/b.txt",
+		"unchanged.txt": "bucket/site/unchanged.txt",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if b, err := ioutil.ReadFile(path); err != nil {
+			t.Fatal(err)
+		} else if string(b) != contents {
+			t.Fatalf("Unexpected contents of %v: %v", name, string(b))
+		}
+	}
+
+	// Downloaded files take the modification time of their object.
+	if info, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil {
+		t.Fatal(err)
+	} else if !info.ModTime().Equal(modified) {
+		t.Fatalf("Unexpected modification time: {Expected: %v, Actual: %v}", modified, info.ModTime())
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "extra.txt")); !os.IsNotExist(err) {
+		t.Fatalf("Expected extraneous file to be removed: %v", err)
+	}
+
+	output := strings.Join(out.output, "")
+	if !strings.HasSuffix(output, "\nTransferred 2 file(s) (34), skipped 1 unchanged file(s), and removed 1 file(s)") {
+		t.Fatalf("Unexpected output: %q", output)
+	}
+}
+
+func TestSyncCommand_Execute_downloadOutside(t *testing.T) {
+	parent, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(parent)
+	dir := filepath.Join(parent, "site")
+
+	buckets := map[string][]client.Object{
+		"bucket": {
+			{Key: "site/a.txt", Size: 10},
+			{Key: "site/../../escaped.txt", Size: 21},
+		},
+	}
+
+	var calls []string
+	s3 := mockSyncS3(buckets, &calls)
+	var con context.Context
+	var out mockOutputter
+
+	sync := NewSync(&s3, &con, []string{"s3://bucket/site", "file://" + dir})
+	if err := sync.Execute(&out); err == nil {
+		t.Fatal("Expected error for a key outside of the destination")
+	}
+
+	expected := []string{"get bucket/site/a.txt"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("Unexpected calls: {Expected: %v, Actual: %v}", expected, calls)
+	}
+
+	if _, err := os.Stat(filepath.Join(parent, "escaped.txt")); !os.IsNotExist(err) {
+		t.Fatalf("Expected no file outside of the destination: %v", err)
+	}
+
+	output := strings.Join(out.output, "")
+	if !strings.Contains(output, "\nFailed: Download: /bucket/site/../../escaped.txt") ||
+		!strings.HasSuffix(output, "\nTransferred 1 file(s) (10), skipped 0 unchanged file(s), and removed 0 file(s)") {
+		t.Fatalf("Unexpected output: %q", output)
+	}
+}
+
+func TestSyncCommand_Execute_remove(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeSyncFiles(t, dir, map[string]string{"a.txt": "a"})
+
+	buckets := map[string][]client.Object{
+		"bucket": {
+			{Key: "a.txt", Size: 1, LastModified: time.Now().Add(time.Hour)},
+			{Key: "b.txt", Size: 1},
+			{Key: "c.txt", Size: 1},
+		},
+	}
+
+	var calls []string
+	s3 := mockSyncS3(buckets, &calls)
+	var con context.Context
+	var out mockOutputter
+
+	var batches int
+	s3.deleteObjectsCallback = func(bucket string, keys []string) ([]client.DeleteError, error) {
+		batches++
+		if bucket != "bucket" || strings.Join(keys, ",") != "b.txt,c.txt" {
+			t.Fatalf("Unexpected objects removed: %v %v", bucket, keys)
+		}
+		return []client.DeleteError{{Key: "c.txt", Code: "AccessDenied", Message: "Access Denied"}}, nil
+	}
+
+	sync := NewSync(&s3, &con, []string{"--delete", "--force-bucket-root", "file://" + dir, "s3://bucket"})
+	if err := sync.Execute(&out); err == nil {
+		t.Fatal("Expected error when objects fail to be removed")
+	} else if batches != 1 {
+		t.Fatalf("Expected objects to be removed in a single batch: %v", batches)
+	}
+
+	output := strings.Join(out.output, "")
+	if !strings.Contains(output, "\nFailed to remove: ") || !strings.Contains(output, "\nRemove: /bucket/b.txt") ||
+		strings.Contains(output, "\nRemove: /bucket/c.txt") ||
+		!strings.HasSuffix(output, "\nTransferred 0 file(s) (0), skipped 1 unchanged file(s), and removed 1 file(s)") {
+		t.Fatalf("Unexpected output: %q", output)
+	}
+}
+
+func TestSyncCommand_Execute_interrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeSyncFiles(t, dir, map[string]string{"a.txt": "a", "b.txt": "b", "c.txt": "c"})
+
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "site/extra.txt"}},
+	}
+
+	var calls []string
+	s3 := mockSyncS3(buckets, &calls)
+	var con context.Context
+	var out mockOutputter
+
+	sync := NewSync(&s3, &con, []string{"--delete", "--concurrency", "1", "file://" + dir, "/bucket/site"})
+	sync.interrupt = make(chan os.Signal, 1)
+
+	// The first upload is interrupted, which prevents any further uploads from starting.
+	s3.putObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) error {
+		calls = append(calls, "put "+bucket+"/"+key)
+		sync.interrupt <- os.Interrupt
+		<-opts.Cancel
+		return client.ErrUploadInterrupted
+	}
+
+	if err := sync.Execute(&out); err != errSyncInterrupted {
+		t.Fatalf("Unexpected error: {Expected: %v, Actual: %v}", errSyncInterrupted, err)
+	}
+
+	expected := []string{"put bucket/site/a.txt"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("Unexpected calls: {Expected: %v, Actual: %v}", expected, calls)
+	}
+
+	output := strings.Join(out.output, "")
+	if output != "\nTransferred 0 file(s) (0), skipped 0 unchanged file(s), and removed 0 file(s)" {
+		t.Fatalf("Unexpected output: %q", output)
+	}
+}
+
+func TestSyncCommand_Execute_copy(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	buckets := map[string][]client.Object{
+		"bucketA": {
+			{Key: "releases/"},
+			{Key: "releases/new.zip", Size: 10, ETag: syncETag("new"), LastModified: past},
+			{Key: "releases/unchanged.zip", Size: 20, ETag: syncETag("unchanged"), LastModified: past},
+			{Key: "releases/same.zip", Size: 30, ETag: syncETag("same"), LastModified: time.Now()},
+			{Key: "releases/modified.zip", Size: 40, ETag: syncETag("modified"), LastModified: time.Now()},
+			{Key: "releases/v1/resized.zip", Size: 50, ETag: syncETag("resized"), LastModified: past},
+		},
+		"bucketB": {
+			{Key: "releases/unchanged.zip", Size: 20, ETag: syncETag("unchanged"), LastModified: time.Now()},
+			{Key: "releases/same.zip", Size: 30, ETag: syncETag("same"), LastModified: past},
+			{Key: "releases/modified.zip", Size: 40, ETag: syncETag("original"), LastModified: past},
+			{Key: "releases/v1/resized.zip", Size: 5, ETag: syncETag("resized"), LastModified: time.Now()},
+			{Key: "releases/old.zip", Size: 1, LastModified: past},
+		},
+	}
+
+	tests := []struct {
+		args   []string
+		calls  []string
+		output string
+	}{
+		{[]string{"releases", "/bucketB/releases", "--concurrency", "2"}, []string{
+			"copy bucketA/releases/modified.zip bucketB/releases/modified.zip",
+			"copy bucketA/releases/new.zip bucketB/releases/new.zip",
+			"copy bucketA/releases/v1/resized.zip bucketB/releases/v1/resized.zip",
+		}, "\nTransferred 3 file(s) (100), skipped 2 unchanged file(s), and removed 0 file(s)"},
+		{[]string{"releases", "/bucketB/releases", "--delete"}, []string{
+			"copy bucketA/releases/modified.zip bucketB/releases/modified.zip",
+			"copy bucketA/releases/new.zip bucketB/releases/new.zip",
+			"copy bucketA/releases/v1/resized.zip bucketB/releases/v1/resized.zip",
+			"delete bucketB/releases/old.zip",
+		}, "\nTransferred 3 file(s) (100), skipped 2 unchanged file(s), and removed 1 file(s)"},
+		{[]string{"releases", "/bucketA/backup", "--concurrency=1"}, []string{
+			"copy bucketA/releases/modified.zip bucketA/backup/modified.zip",
+			"copy bucketA/releases/new.zip bucketA/backup/new.zip",
+			"copy bucketA/releases/same.zip bucketA/backup/same.zip",
+			"copy bucketA/releases/unchanged.zip bucketA/backup/unchanged.zip",
+			"copy bucketA/releases/v1/resized.zip bucketA/backup/v1/resized.zip",
+		}, "\nTransferred 5 file(s) (150), skipped 0 unchanged file(s), and removed 0 file(s)"},
+	}
+
+	for _, test := range tests {
+		var calls []string
+		s3 := mockSyncS3(buckets, &calls)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucketA")
+
+		sync := NewSync(&s3, &con, test.args)
+		if err := sync.Execute(&out); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.args, err)
+		}
+
+		sort.Strings(calls)
+		if !reflect.DeepEqual(calls, test.calls) {
+			t.Fatalf("Unexpected calls for %v: {Expected: %v, Actual: %v}", test.args, test.calls, calls)
+		}
+
+		output := strings.Join(out.output, "")
+		if !strings.HasSuffix(output, test.output) {
+			t.Fatalf("Unexpected output for %v: {Expected: %q, Actual: %q}", test.args, test.output, output)
+		} else if !strings.Contains(output, "\nCopy: /bucketA/releases/new.zip -> ") {
+			t.Fatalf("Expected each copy to be output for %v: %q", test.args, output)
+		}
+	}
+}
+
+func TestSyncCommand_Execute_invalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeSyncFiles(t, dir, map[string]string{"file.txt": "file", "local/a.txt": "a"})
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "site/a.txt"}},
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"./"},
+		{".", "./other"},
+		{"/bucket/site", "/bucket/site/"},
+		{"/bucket/site", "/bucket/site/sub"},
+		{"/bucket/site", "/bucket"},
+		{"--concurrency", "0", ".", "/bucket/site"},
+		{"--concurrency=abc", ".", "/bucket/site"},
+		{".", "/"},
+		{"./fake", "/bucket/site"},
+		{"./file.txt", "/bucket/site"},
+		{".", "/fake/site"},
+		{"/bucket/fake", "."},
+		{"/fake", "."},
+		{"/bucket/site", "./file.txt"},
+		{"file://" + dir, "file://" + dir + "/local"},
+		{"--delete", ".", "s3://bucket"},
+		{"--delete", ".", "/bucket/"},
+	} {
+		var calls []string
+		s3 := mockSyncS3(buckets, &calls)
+		var con context.Context
+		var out mockOutputter
+
+		sync := NewSync(&s3, &con, args)
+		if err := sync.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		} else if len(calls) > 0 {
+			t.Fatalf("Unexpected calls for invalid args %v: %v", args, calls)
+		}
+	}
+
+	// Ambiguous paths, which exist locally but are not marked as local
+	for _, args := range [][]string{
+		{"local", "/bucket/site"},
+		{"--delete", "/bucket/site", "local"},
+		{"/bucket/site", dir},
+	} {
+		var calls []string
+		s3 := mockSyncS3(buckets, &calls)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		sync := NewSync(&s3, &con, args)
+		if err := sync.Execute(&out); err == nil || !strings.HasPrefix(err.Error(), "Ambiguous path") {
+			t.Fatalf("Expected ambiguous path error for %v: %v", args, err)
+		} else if len(calls) > 0 {
+			t.Fatalf("Unexpected calls for ambiguous args %v: %v", args, calls)
+		}
+	}
+
+	// S3 errors
+	{
+		mockErr := errors.New("Mock Error")
+		var calls []string
+		s3 := mockSyncS3(buckets, &calls)
+		var con context.Context
+		var out mockOutputter
+
+		s3.lsObjectsCallback = func(bucket, prefix string, fn func([]client.Object) bool) error {
+			return mockErr
+		}
+
+		sync := NewSync(&s3, &con, []string{".", "/bucket/site"})
+		if err := sync.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+
+		s3 = mockSyncS3(buckets, &calls)
+		s3.putObjectCallback = func(bucket, key string, f *os.File, opts client.UploadOptions) error {
+			return mockErr
+		}
+
+		sync = NewSync(&s3, &con, []string{".", "/bucket/site"})
+		if err := sync.Execute(&out); err == nil {
+			t.Fatal("Expected error when a transfer fails")
+		}
+
+		expected := "\nFailed: Upload: " + filepath.Join(dir, "file.txt") + " -> /bucket/site/file.txt: Mock Error"
+		if output := strings.Join(out.output, ""); !strings.Contains(output, expected) {
+			t.Fatalf("Unexpected output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+}
+
+func TestSyncCommand_IsLongRunning(t *testing.T) {
+	sync := NewSync(nil, nil, nil)
+	if !sync.IsLongRunning() {
+		t.Fatal("Expected SyncCommand to always be long running")
+	}
+}
+
+func TestNewSync(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	args := []string{".", "bucket"}
+
+	sync := NewSync(&s3, &con, args)
+	if sync.s3 != &s3 {
+		t.Fatalf("Unexpected S3Client: {Expected: %v, Actual: %v}", &s3, sync.s3)
+	} else if sync.con != &con {
+		t.Fatalf("Unexpected Context: {Expected: %v, Actual: %v}", &con, sync.con)
+	} else if !reflect.DeepEqual(sync.args, args) {
+		t.Fatalf("Unexpected args: {Expected: %v, Actual: %v}", args, sync.args)
+	}
+}
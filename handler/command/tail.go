@@ -0,0 +1,230 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// tailFlagFollow indicates that a folder should be followed, printing new objects as they are created.
+	tailFlagFollow = "f"
+
+	// tailPollInterval is the time between listings of a followed folder.
+	tailPollInterval = time.Second * 2
+)
+
+// TailCommand prints the last lines of objects, or follows a folder and prints new objects as they are created.
+type TailCommand struct {
+	s3  S3Client
+	con *context.Context
+
+	args []string
+
+	interval  time.Duration
+	interrupt chan os.Signal
+}
+
+// Execute performs a 'tail' command by reading each target object backward in chunks, using ranged requests,
+// until enough lines have been found to print.
+//
+// When following, the target is instead a folder that is polled for new objects until interrupted.
+func (tail TailCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(tail.args, lineFlagCount)
+	if flags.Has(tailFlagFollow) {
+		return tail.follow(out, flags)
+	}
+
+	n, targets, err := resolveLineTargets(tail.s3, tail.con, flags)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range targets {
+		if len(targets) > 1 {
+			out.Write("\n==> " + displayPath(t.path) + " <==")
+		}
+
+		if err := tail.print(out, t.path, t.size, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// print outputs the last n lines of an object of the size provided.
+func (tail TailCommand) print(out Outputter, p []string, size int64, n int) error {
+	bucket, key := splitKeyPath(p)
+
+	// Chunks are read from the end of the object, and prepended to the output as they are read.
+	var chunks [][]byte
+	var lines int
+	for end := size; end > 0 && lines < n; end -= rangeChunkSize {
+		start := end - rangeChunkSize
+		if start < 0 {
+			start = 0
+		}
+
+		b, err := readRange(tail.s3, bucket, key, start, end)
+		if err != nil {
+			return err
+		}
+
+		// Truncate the chunk before the first newline required, ignoring the newline that terminates the
+		// final line of the object.
+		for i := len(b) - 1; i >= 0; i-- {
+			if b[i] != '\n' || start+int64(i) == size-1 {
+				continue
+			}
+
+			lines++
+			if lines == n {
+				b = b[i+1:]
+				break
+			}
+		}
+
+		chunks = append([][]byte{b}, chunks...)
+	}
+
+	out.Write("\n" + string(bytes.Join(chunks, nil)))
+	return nil
+}
+
+// follow polls a folder for new objects, printing the contents of each in the order they were created, until
+// interrupted.
+//
+// Objects that exist when following begins are not printed, apart from the last lines of the most recent one.
+func (tail TailCommand) follow(out Outputter, flags util.Flags) error {
+	if len(flags.Args) != 1 {
+		return errors.New("Follow requires a single target folder.")
+	}
+
+	n, err := lineCount(flags)
+	if err != nil {
+		return err
+	}
+
+	p := tail.con.CalculatePath(flags.Args[0])
+	if len(p) == 0 {
+		return fmt.Errorf("Target is not a folder: %v", displayPath(p))
+	}
+
+	bucket, prefix := splitFolderPath(p)
+	if ok, err := tail.s3.BucketExists(bucket); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("No such bucket: " + displayPath(p[:1]))
+	}
+
+	cancel, stop := cancelOnInterrupt(tail.interrupt)
+	defer stop()
+
+	// Record the existing objects, and print the end of the most recent to provide context.
+	seen := make(map[string]bool)
+	existing, err := tail.newObjects(bucket, prefix, seen)
+	if err != nil {
+		return err
+	} else if len(existing) > 0 {
+		latest := existing[len(existing)-1]
+		if err := tail.print(out, []string{bucket, latest.Key}, latest.Size, n); err != nil {
+			return err
+		}
+	}
+
+	out.Write("\nFollowing " + displayPath(p) + context.PathDelimiter + ", press Ctrl+C to stop...")
+
+	for {
+		select {
+		case <-cancel:
+			return nil
+		case <-time.After(tail.interval):
+		}
+
+		objects, err := tail.newObjects(bucket, prefix, seen)
+		if err != nil {
+			return err
+		}
+
+		for _, o := range objects {
+			if err := tail.stream(out, bucket, o.Key); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newObjects lists the objects in a folder that have not yet been seen, sorted in the order they were created,
+// and records them as seen.
+func (tail TailCommand) newObjects(bucket, prefix string, seen map[string]bool) ([]client.Object, error) {
+	var objects []client.Object
+	err := tail.s3.LsObjects(bucket, prefix, func(page []client.Object) bool {
+		for _, o := range page {
+			if !seen[o.Key] {
+				seen[o.Key] = true
+				objects = append(objects, o)
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(byCreation(objects))
+	return objects, nil
+}
+
+// stream outputs the entire contents of an object, preceded by a header naming it.
+func (tail TailCommand) stream(out Outputter, bucket, key string) error {
+	body, err := tail.s3.OpenObject(bucket, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	out.Write("\n==> " + displayPath([]string{bucket, key}) + " <==\n")
+	_, err = io.Copy(outputWriter{out}, body)
+	return err
+}
+
+// IsLongRunning returns false, as the contents are output as they are received and the loading indicator would
+// otherwise interfere with them.
+func (TailCommand) IsLongRunning() bool {
+	return false
+}
+
+// NewTail initializes and returns a TailCommand.
+func NewTail(s3 S3Client, con *context.Context, args []string) TailCommand {
+	return TailCommand{
+		s3:   s3,
+		con:  con,
+		args: args,
+
+		interval: tailPollInterval,
+	}
+}
+
+// byCreation sorts objects by their modification time, and then lexically by key for objects modified at the
+// same time.
+type byCreation []client.Object
+
+func (b byCreation) Len() int      { return len(b) }
+func (b byCreation) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byCreation) Less(i, j int) bool {
+	if !b[i].LastModified.Equal(b[j].LastModified) {
+		return b[i].LastModified.Before(b[j].LastModified)
+	}
+
+	return b[i].Key < b[j].Key
+}
@@ -0,0 +1,225 @@
+package command
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+func TestTailCommand_Execute(t *testing.T) {
+	large := mockLineContents(20000)
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "large.log"}, {Key: "small.txt"}, {Key: "unterminated.txt"}, {Key: "empty.txt"}},
+	}
+	contents := map[string]string{
+		"bucket/large.log":        large,
+		"bucket/small.txt":        "a\nb\nc\n",
+		"bucket/unterminated.txt": "a\nb\nc",
+		"bucket/empty.txt":        "",
+	}
+
+	tests := []struct {
+		args      []string
+		expected  string
+		maxLength int64
+	}{
+		{[]string{"small.txt"}, "\na\nb\nc\n", 6},
+		{[]string{"-n", "2", "small.txt"}, "\nb\nc\n", 6},
+		{[]string{"-n", "2", "unterminated.txt"}, "\nb\nc", 5},
+		{[]string{"-n", "0", "small.txt"}, "\n", 0},
+		{[]string{"empty.txt"}, "\n", 0},
+		{[]string{"large.log"}, "\n" + large[len(large)-110:], rangeChunkSize},
+		{[]string{"-n", "10000", "large.log"}, "\n" + large[len(large)-110000:], 2 * rangeChunkSize},
+		{[]string{"-n", "99999", "large.log"}, "\n" + large, int64(len(large))},
+		{[]string{"-n1", "small.txt", "/bucket/large.log"}, "\n==> /bucket/small.txt <==\nc\n\n==> /bucket/large.log <==\nline 19999\n", 6 + rangeChunkSize},
+	}
+
+	for _, test := range tests {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		requested := mockObjectRanges(&s3, contents)
+
+		tail := NewTail(&s3, &con, test.args)
+		if err := tail.Execute(&out); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.args, err)
+		}
+
+		if output := strings.Join(out.output, ""); output != test.expected {
+			t.Fatalf("Unexpected output for %v: {Expected: %q, Actual: %q}", test.args, test.expected, output)
+		} else if *requested > test.maxLength {
+			t.Fatalf("Unexpected bytes requested for %v: {Expected: <= %v, Actual: %v}", test.args, test.maxLength, *requested)
+		}
+	}
+}
+
+func TestTailCommand_Execute_invalid(t *testing.T) {
+	buckets := map[string][]client.Object{
+		"bucket": {{Key: "a.txt"}, {Key: "folder/b.txt"}},
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"folder"},
+		{"fake.txt"},
+		{"-n", "abc", "a.txt"},
+	} {
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+
+		s3.objectExistsCallback = func(bucket, key string) (bool, error) {
+			t.Fatalf("ObjectExists should not be called, as HeadObject determines whether the object exists: %v", args)
+			return false, nil
+		}
+		s3.headObjectCallback = func(bucket, key string) (client.ObjectInfo, error) {
+			return client.ObjectInfo{}, client.ErrObjectNotFound
+		}
+
+		tail := NewTail(&s3, &con, args)
+		if err := tail.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+
+	// S3 error
+	{
+		s3 := newMockS3Listing(buckets)
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		mockErr := errors.New("Mock Error")
+
+		s3.headObjectCallback = func(bucket, key string) (client.ObjectInfo, error) {
+			return client.ObjectInfo{Key: key, Size: 10}, nil
+		}
+		s3.openObjectRangeCallback = func(bucket, key string, start, end int64) (io.ReadCloser, error) {
+			return nil, mockErr
+		}
+
+		tail := NewTail(&s3, &con, []string{"a.txt"})
+		if err := tail.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestTailCommand_Execute_follow(t *testing.T) {
+	now := time.Now()
+	contents := map[string]string{
+		"bucket/logs/2017-01-01/1.log": "old\nolder\nnewest\n",
+		"bucket/logs/2017-01-01/2.log": "second\n",
+		"bucket/logs/2017-01-01/3.log": "third\n",
+		"bucket/logs/2017-01-02/1.log": "fourth\n",
+	}
+
+	// Each poll of the folder lists additional objects, which are not necessarily listed in the order they were
+	// created.
+	polls := [][]client.Object{
+		{{Key: "logs/2017-01-01/1.log", Size: 17, LastModified: now}},
+		{{Key: "logs/2017-01-01/1.log", Size: 17, LastModified: now}},
+		{
+			{Key: "logs/2017-01-01/1.log", Size: 17, LastModified: now},
+			{Key: "logs/2017-01-01/3.log", LastModified: now.Add(time.Minute * 2)},
+			{Key: "logs/2017-01-01/2.log", LastModified: now.Add(time.Minute)},
+		},
+		{
+			{Key: "logs/2017-01-01/1.log", Size: 17, LastModified: now},
+			{Key: "logs/2017-01-01/2.log", LastModified: now.Add(time.Minute)},
+			{Key: "logs/2017-01-01/3.log", LastModified: now.Add(time.Minute * 2)},
+			{Key: "logs/2017-01-02/1.log", LastModified: now.Add(time.Minute * 2)},
+		},
+	}
+
+	var s3 mockS3Client
+	var con context.Context
+	var out mockOutputter
+	con.UpdatePath("bucket")
+
+	interrupt := make(chan os.Signal, 1)
+	mockObjectRanges(&s3, contents)
+	s3.openObjectCallback = mockOpenObject(contents)
+	s3.bucketExistsCallback = func(bucket string) (bool, error) {
+		return true, nil
+	}
+
+	var poll int
+	s3.lsObjectsCallback = func(bucket, prefix string, fn func([]client.Object) bool) error {
+		if bucket != "bucket" || prefix != "logs/" {
+			t.Fatalf("Unexpected bucket/prefix polled: %v/%v", bucket, prefix)
+		}
+
+		fn(polls[poll])
+		poll++
+
+		// Interrupt once every poll has been listed.
+		if poll == len(polls) {
+			interrupt <- os.Interrupt
+		}
+		return nil
+	}
+
+	tail := NewTail(&s3, &con, []string{"-f", "-n", "1", "logs"})
+	tail.interval = time.Millisecond
+	tail.interrupt = interrupt
+	if err := tail.Execute(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"\nnewest\n",
+		"\nFollowing /bucket/logs/, press Ctrl+C to stop...",
+		"\n==> /bucket/logs/2017-01-01/2.log <==\n", "second\n",
+		"\n==> /bucket/logs/2017-01-01/3.log <==\n", "third\n",
+		"\n==> /bucket/logs/2017-01-02/1.log <==\n", "fourth\n",
+	}, "")
+	if output := strings.Join(out.output, ""); output != expected {
+		t.Fatalf("Unexpected output: {Expected: %q, Actual: %q}", expected, output)
+	}
+
+	// Invalid targets
+	for _, args := range [][]string{
+		{"-f"},
+		{"-f", "a", "b"},
+		{"-f", "/"},
+		{"-f", "-n", "abc", "logs"},
+	} {
+		tail := NewTail(&s3, &con, args)
+		if err := tail.Execute(&out); err == nil {
+			t.Fatalf("Expected error for invalid args: %v", args)
+		}
+	}
+}
+
+func TestTailCommand_IsLongRunning(t *testing.T) {
+	tail := NewTail(nil, nil, nil)
+
+	if tail.IsLongRunning() {
+		t.Fatal("Expected TailCommand to never be long running")
+	}
+}
+
+func TestNewTail(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	args := []string{"a.txt"}
+
+	tail := NewTail(&s3, &con, args)
+	if tail.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on tail command: %v", tail.s3)
+	} else if tail.con != &con {
+		t.Fatalf("Unexpected Context stored on tail command: %v", tail.con)
+	} else if tail.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on tail command: %v", tail.args)
+	} else if tail.interval != tailPollInterval {
+		t.Fatalf("Unexpected poll interval stored on tail command: %v", tail.interval)
+	}
+}
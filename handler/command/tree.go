@@ -0,0 +1,212 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+	"github.com/KyleBanks/s3fs/handler/command/util"
+)
+
+const (
+	// treeFlagDepth limits the number of folder levels to descend into.
+	treeFlagDepth = "L"
+
+	// treeFlagHuman indicates that the total size should be human-readable.
+	treeFlagHuman = "h"
+
+	// treeBranch precedes an entry that has siblings following it.
+	treeBranch = "├── "
+
+	// treeLastBranch precedes the final entry of a folder.
+	treeLastBranch = "└── "
+
+	// treeIndent indents the contents of a folder that has siblings following it.
+	treeIndent = "│   "
+
+	// treeLastIndent indents the contents of the final folder of a folder.
+	treeLastIndent = "    "
+)
+
+// TreeCommand renders a folder, and all of its subfolders, as an indented tree.
+type TreeCommand struct {
+	s3  S3Client
+	con *context.Context
+
+	args []string
+}
+
+// treeEntry is a single folder or file rendered by a TreeCommand.
+type treeEntry struct {
+	name     string
+	isFolder bool
+	size     int64
+
+	// bucket and prefix locate the contents of a folder.
+	bucket string
+	prefix string
+}
+
+// treeSummary totals the entries rendered by a TreeCommand.
+type treeSummary struct {
+	folders int
+	files   int
+	bytes   int64
+}
+
+// Execute performs a 'tree' command by rendering the contents of the pwd, or a provided path, followed by a
+// summary of the number of folders and files, and their total size.
+func (tree TreeCommand) Execute(out Outputter) error {
+	flags := util.ParseFlags(tree.args, treeFlagDepth)
+
+	// Determine the maximum depth, where zero is unlimited.
+	var depth int
+	if flags.Has(treeFlagDepth) {
+		var err error
+		if depth, err = strconv.Atoi(flags.Value(treeFlagDepth)); err != nil || depth < 1 {
+			return fmt.Errorf("Invalid depth: %v", flags.Value(treeFlagDepth))
+		}
+	}
+
+	var target string
+	if len(flags.Args) > 0 {
+		target = flags.Args[0]
+	}
+
+	// Determine the top-level entries, which are the buckets when at the root.
+	var entries []treeEntry
+	s3Path := tree.con.CalculatePath(target)
+	if len(s3Path) == 0 {
+		buckets, err := tree.s3.LsBuckets()
+		if err != nil {
+			return err
+		}
+
+		for _, b := range buckets {
+			entries = append(entries, treeEntry{name: b + context.PathDelimiter, isFolder: true, bucket: b})
+		}
+	} else {
+		bucket, prefix := splitFolderPath(s3Path)
+
+		// The context path has already been validated, so only explicitly provided folders need to be checked.
+		if len(target) > 0 {
+			if ok, err := folderExists(tree.s3, bucket, prefix); err != nil {
+				return err
+			} else if !ok {
+				return errors.New("No such directory: " + displayPath(s3Path))
+			}
+		}
+
+		var err error
+		if entries, err = tree.children(bucket, prefix); err != nil {
+			return err
+		}
+	}
+
+	out.Write("\n" + displayPath(s3Path))
+
+	var summary treeSummary
+	if err := tree.render(out, entries, "", 1, depth, &summary); err != nil {
+		return err
+	}
+
+	size := fmt.Sprintf("%d bytes", summary.bytes)
+	if flags.Has(treeFlagHuman) {
+		size = util.HumanSize(summary.bytes)
+	}
+	out.Write(fmt.Sprintf("\n\n%d folders, %d files, %v total", summary.folders, summary.files, size))
+
+	return nil
+}
+
+// render outputs each entry at the provided indentation and level, descending into folders until the maximum
+// depth is reached, and totals the rendered entries in the summary.
+func (tree TreeCommand) render(out Outputter, entries []treeEntry, indent string, level, depth int, summary *treeSummary) error {
+	for i, e := range entries {
+		branch, childIndent := treeBranch, treeIndent
+		if i == len(entries)-1 {
+			branch, childIndent = treeLastBranch, treeLastIndent
+		}
+
+		out.Write("\n" + indent + branch + e.name)
+
+		if !e.isFolder {
+			summary.files++
+			summary.bytes += e.size
+			continue
+		}
+
+		summary.folders++
+		if depth > 0 && level >= depth {
+			continue
+		}
+
+		children, err := tree.children(e.bucket, e.prefix)
+		if err != nil {
+			return err
+		} else if err := tree.render(out, children, indent+childIndent, level+1, depth, summary); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// children returns the folders and files within a prefix, sorted by name.
+func (tree TreeCommand) children(bucket, prefix string) ([]treeEntry, error) {
+	var entries []treeEntry
+
+	err := tree.s3.LsDir(bucket, prefix, func(folders []string, files []client.Object) bool {
+		for _, f := range folders {
+			entries = append(entries, treeEntry{
+				name:     strings.TrimPrefix(f, prefix),
+				isFolder: true,
+				bucket:   bucket,
+				prefix:   f,
+			})
+		}
+
+		for _, f := range files {
+			// Skip the folder itself.
+			name := strings.TrimPrefix(f.Key, prefix)
+			if len(name) == 0 {
+				continue
+			}
+
+			entries = append(entries, treeEntry{name: name, size: f.Size})
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(treeEntries(entries))
+	return entries, nil
+}
+
+// IsLongRunning returns true because 'tree' requires network operations.
+func (TreeCommand) IsLongRunning() bool {
+	return true
+}
+
+// NewTree initializes and returns a TreeCommand.
+func NewTree(s3 S3Client, con *context.Context, args []string) TreeCommand {
+	return TreeCommand{
+		s3:   s3,
+		con:  con,
+		args: args,
+	}
+}
+
+// treeEntries implements sort.Interface to sort entries by name.
+type treeEntries []treeEntry
+
+func (t treeEntries) Len() int           { return len(t) }
+func (t treeEntries) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t treeEntries) Less(i, j int) bool { return t[i].name < t[j].name }
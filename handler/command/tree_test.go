@@ -0,0 +1,175 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/s3fs/client"
+	"github.com/KyleBanks/s3fs/handler/command/context"
+)
+
+func TestTreeCommand_Execute(t *testing.T) {
+	objects := []client.Object{
+		{Key: "folder/"},
+		{Key: "folder/a.txt", Size: 1024},
+		{Key: "folder/sub/b.txt", Size: 512},
+		{Key: "folder/sub/deeper/c.txt", Size: 512},
+		{Key: "folder/z.txt", Size: 1},
+	}
+
+	// Full tree
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket/folder")
+		s3.lsDirCallback = mockLsDir(objects)
+
+		tree := NewTree(&s3, &con, nil)
+		if err := tree.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := strings.Join([]string{
+			"\n/bucket/folder",
+			"\n├── a.txt",
+			"\n├── sub/",
+			"\n│   ├── b.txt",
+			"\n│   └── deeper/",
+			"\n│       └── c.txt",
+			"\n└── z.txt",
+			"\n\n2 folders, 4 files, 2049 bytes total",
+		}, "")
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected tree output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+
+	// Limited depth, human-readable, with a path
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		s3.lsDirCallback = mockLsDir(objects)
+		s3.pathExistsCallback = func(bucket, prefix string) (bool, error) {
+			return bucket == "bucket" && prefix == "folder/", nil
+		}
+
+		tree := NewTree(&s3, &con, []string{"-L", "1", "-h", "folder"})
+		if err := tree.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := strings.Join([]string{
+			"\n/bucket/folder",
+			"\n├── a.txt",
+			"\n├── sub/",
+			"\n└── z.txt",
+			"\n\n1 folders, 2 files, 1.0K total",
+		}, "")
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected tree output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+
+	// Root
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		s3.lsBucketsCallback = func() ([]string, error) {
+			return []string{"bucket1", "bucket2"}, nil
+		}
+		s3.lsDirCallback = mockLsDir(objects[1:2])
+
+		tree := NewTree(&s3, &con, []string{"-L", "2"})
+		if err := tree.Execute(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := strings.Join([]string{
+			"\n/",
+			"\n├── bucket1/",
+			"\n│   └── folder/",
+			"\n└── bucket2/",
+			"\n    └── folder/",
+			"\n\n4 folders, 0 files, 0 bytes total",
+		}, "")
+		if output := strings.Join(out.output, ""); output != expected {
+			t.Fatalf("Unexpected tree output: {Expected: %q, Actual: %q}", expected, output)
+		}
+	}
+
+	// Invalid depth
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+
+		for _, depth := range []string{"0", "-1", "abc"} {
+			tree := NewTree(&s3, &con, []string{"-L", depth})
+			if err := tree.Execute(&out); err == nil {
+				t.Fatalf("Expected error for invalid depth: %v", depth)
+			}
+		}
+	}
+
+	// Non-existent path
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		s3.bucketExistsCallback = func(bucket string) (bool, error) {
+			return false, nil
+		}
+
+		tree := NewTree(&s3, &con, []string{"fake"})
+		if err := tree.Execute(&out); err == nil {
+			t.Fatal("Expected error for non-existent path")
+		} else if len(out.output) != 0 {
+			t.Fatalf("Expected no output for non-existent path: %v", out.output)
+		}
+	}
+
+	// S3 error
+	{
+		var s3 mockS3Client
+		var con context.Context
+		var out mockOutputter
+		con.UpdatePath("bucket")
+		mockErr := errors.New("Mock Error")
+		s3.lsDirCallback = func(bucket, prefix string, fn func([]string, []client.Object) bool) error {
+			return mockErr
+		}
+
+		tree := NewTree(&s3, &con, nil)
+		if err := tree.Execute(&out); err != mockErr {
+			t.Fatalf("Expected error to be passed up the stack: %v", err)
+		}
+	}
+}
+
+func TestTreeCommand_IsLongRunning(t *testing.T) {
+	tree := NewTree(nil, nil, nil)
+
+	if !tree.IsLongRunning() {
+		t.Fatal("Expected TreeCommand to always be long running")
+	}
+}
+
+func TestNewTree(t *testing.T) {
+	var s3 mockS3Client
+	var con context.Context
+	args := []string{"folder"}
+
+	tree := NewTree(&s3, &con, args)
+	if tree.s3 != &s3 {
+		t.Fatalf("Unexpected S3 client stored on tree command: %v", tree.s3)
+	} else if tree.con != &con {
+		t.Fatalf("Unexpected Context stored on tree command: %v", tree.con)
+	} else if tree.args[0] != args[0] {
+		t.Fatalf("Unexpected args stored on tree command: %v", tree.args)
+	}
+}
@@ -0,0 +1,124 @@
+package util
+
+import (
+	"strings"
+)
+
+const (
+	// flagPrefix is the prefix of a short flag, such as '-l'.
+	flagPrefix = "-"
+
+	// longFlagPrefix is the prefix of a long flag, such as '--force'.
+	longFlagPrefix = "--"
+
+	// flagValueSeparator separates a long flag from its inline value, such as '--region=us-east-1'.
+	flagValueSeparator = "="
+)
+
+// Flags contains the flags and positional arguments parsed from a command's arguments.
+type Flags struct {
+	values map[string][]string
+
+	// Args contains the positional (non-flag) arguments, in the order they were provided.
+	Args []string
+}
+
+// ParseFlags separates the flags in args from the positional arguments.
+//
+// Short flags may be combined (ie. '-lh' is equivalent to '-l -h') and long flags are prefixed by '--'. Flags named
+// in valued take a value, either from the following argument (ie. '-n 10', '--region us-west-2'), or inline
+// (ie. '-n10', '--region=us-west-2'). A lone '--' ends flag parsing, and a lone '-' is treated as positional.
+func ParseFlags(args []string, valued ...string) Flags {
+	f := Flags{
+		values: make(map[string][]string),
+	}
+
+	isValued := make(map[string]bool)
+	for _, v := range valued {
+		isValued[v] = true
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+
+		// Ignore empty arguments, such as those produced by repeated spaces.
+		case len(arg) == 0:
+
+		// Everything following the terminator is positional.
+		case arg == longFlagPrefix:
+			for _, a := range args[i+1:] {
+				if len(a) > 0 {
+					f.Args = append(f.Args, a)
+				}
+			}
+			return f
+
+		// Long flag, with an optional inline value.
+		case strings.HasPrefix(arg, longFlagPrefix):
+			name := strings.TrimPrefix(arg, longFlagPrefix)
+			if idx := strings.Index(name, flagValueSeparator); idx >= 0 {
+				f.add(name[:idx], name[idx+1:])
+			} else if isValued[name] && i+1 < len(args) {
+				i++
+				f.add(name, args[i])
+			} else {
+				f.add(name, "")
+			}
+
+		// Short flag(s), where a valued flag consumes the remainder of the group or the following argument.
+		case strings.HasPrefix(arg, flagPrefix) && len(arg) > len(flagPrefix):
+			group := strings.TrimPrefix(arg, flagPrefix)
+			for j, c := range group {
+				name := string(c)
+				if !isValued[name] {
+					f.add(name, "")
+					continue
+				}
+
+				if rest := group[j+len(name):]; len(rest) > 0 {
+					f.add(name, rest)
+				} else if i+1 < len(args) {
+					i++
+					f.add(name, args[i])
+				} else {
+					f.add(name, "")
+				}
+				break
+			}
+
+		// Positional argument.
+		default:
+			f.Args = append(f.Args, arg)
+		}
+	}
+
+	return f
+}
+
+// add records a flag and its value.
+func (f *Flags) add(name, value string) {
+	f.values[name] = append(f.values[name], value)
+}
+
+// Has returns true if the named flag was provided.
+func (f Flags) Has(name string) bool {
+	_, ok := f.values[name]
+	return ok
+}
+
+// Value returns the last value provided for the named flag, or an empty string if it wasn't provided.
+func (f Flags) Value(name string) string {
+	values := f.values[name]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[len(values)-1]
+}
+
+// Values returns every value provided for the named flag, in the order they were provided.
+func (f Flags) Values(name string) []string {
+	return f.values[name]
+}
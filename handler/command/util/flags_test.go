@@ -0,0 +1,83 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		args   []string
+		valued []string
+
+		flags  map[string][]string
+		params []string
+	}{
+		// No args
+		{nil, nil, map[string][]string{}, nil},
+
+		// Positional only, ignoring empty args
+		{[]string{"a", "", "b"}, nil, map[string][]string{}, []string{"a", "b"}},
+
+		// Short flags, separate and combined
+		{[]string{"-l", "a", "-hr"}, nil, map[string][]string{"l": {""}, "h": {""}, "r": {""}}, []string{"a"}},
+
+		// Long flags
+		{[]string{"--force", "a"}, nil, map[string][]string{"force": {""}}, []string{"a"}},
+
+		// Valued flags
+		{[]string{"-n", "10", "a"}, []string{"n"}, map[string][]string{"n": {"10"}}, []string{"a"}},
+		{[]string{"-ln5", "a"}, []string{"n"}, map[string][]string{"l": {""}, "n": {"5"}}, []string{"a"}},
+		{[]string{"--region", "r", "a"}, []string{"region"}, map[string][]string{"region": {"r"}}, []string{"a"}},
+		{[]string{"--region=r", "a"}, nil, map[string][]string{"region": {"r"}}, []string{"a"}},
+
+		// Repeated valued flags
+		{[]string{"--exclude", "a", "--exclude", "b"}, []string{"exclude"}, map[string][]string{"exclude": {"a", "b"}}, nil},
+
+		// Valued flag without a value
+		{[]string{"-n"}, []string{"n"}, map[string][]string{"n": {""}}, nil},
+
+		// Terminator and lone dash
+		{[]string{"-l", "-", "--", "-r"}, nil, map[string][]string{"l": {""}}, []string{"-", "-r"}},
+	}
+
+	for _, test := range tests {
+		f := ParseFlags(test.args, test.valued...)
+
+		if !reflect.DeepEqual(f.values, test.flags) {
+			t.Fatalf("Unexpected flags for %v: {Expected: %v, Actual: %v}", test.args, test.flags, f.values)
+		} else if !reflect.DeepEqual(f.Args, test.params) {
+			t.Fatalf("Unexpected args for %v: {Expected: %v, Actual: %v}", test.args, test.params, f.Args)
+		}
+	}
+}
+
+func TestFlags_Has(t *testing.T) {
+	f := ParseFlags([]string{"-l", "--force"})
+
+	if !f.Has("l") || !f.Has("force") {
+		t.Fatalf("Expected provided flags to be found: %v", f)
+	} else if f.Has("r") {
+		t.Fatalf("Unexpected flag found: %v", f)
+	}
+}
+
+func TestFlags_Value(t *testing.T) {
+	f := ParseFlags([]string{"-n", "1", "-n", "2"}, "n")
+
+	if v := f.Value("n"); v != "2" {
+		t.Fatalf("Expected last value to be returned: %v", v)
+	} else if v := f.Value("x"); v != "" {
+		t.Fatalf("Expected empty value for missing flag: %v", v)
+	}
+}
+
+func TestFlags_Values(t *testing.T) {
+	f := ParseFlags([]string{"-n", "1", "-n", "2"}, "n")
+
+	if v := f.Values("n"); !reflect.DeepEqual(v, []string{"1", "2"}) {
+		t.Fatalf("Unexpected values returned: %v", v)
+	} else if v := f.Values("x"); len(v) != 0 {
+		t.Fatalf("Expected no values for missing flag: %v", v)
+	}
+}
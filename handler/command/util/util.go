@@ -2,14 +2,21 @@
 package util
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"os/user"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
 const (
 	// homeSymbol defines the symbol representing the HOME directory.
 	homeSymbol = "~"
+
+	// sizeUnits are the unit suffixes used for human-readable sizes, each 1024 times larger than the last.
+	sizeUnits = "KMGTPE"
 )
 
 // AbsPath converts a file/directory path into an absolute path, including support for handling the home directory symbol
@@ -28,3 +35,44 @@ func AbsPath(path string) (string, error) {
 	// Convert the path to an absolute path.
 	return filepath.Abs(path)
 }
+
+// HumanSize formats a number of bytes in a human-readable form, using the largest unit that keeps the value at or
+// above one, in the style of 'ls -h' (ie. 512, 1.5K, 23M).
+func HumanSize(bytes int64) string {
+	if bytes < 1024 {
+		return fmt.Sprintf("%d", bytes)
+	}
+
+	size := float64(bytes)
+	var unit byte
+	for i := 0; i < len(sizeUnits) && size >= 1024; i++ {
+		size /= 1024
+		unit = sizeUnits[i]
+	}
+
+	// Single digit sizes keep a decimal place for precision.
+	if size < 10 {
+		return fmt.Sprintf("%.1f%c", size, unit)
+	}
+	return fmt.Sprintf("%.0f%c", size, unit)
+}
+
+// ParseSize parses a number of bytes with an optional unit suffix, as output by HumanSize (ie. 512, 16K, 64M).
+func ParseSize(s string) (int64, error) {
+	num := s
+	multiplier := int64(1)
+	if len(num) > 0 {
+		if i := strings.IndexByte(sizeUnits, num[len(num)-1]); i >= 0 {
+			multiplier = int64(1) << (10 * uint(i+1))
+			num = num[:len(num)-1]
+		}
+	}
+
+	// Sizes too large to be represented are invalid, rather than overflowing.
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 || n > math.MaxInt64/multiplier {
+		return 0, errors.New("Invalid size: " + s)
+	}
+
+	return n * multiplier, nil
+}
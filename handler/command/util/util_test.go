@@ -43,3 +43,56 @@ func TestAbsPath(t *testing.T) {
 		}
 	}
 }
+
+func TestHumanSize(t *testing.T) {
+	tests := []struct {
+		input  int64
+		output string
+	}{
+		{0, "0"},
+		{512, "512"},
+		{1023, "1023"},
+		{1024, "1.0K"},
+		{1536, "1.5K"},
+		{10 * 1024, "10K"},
+		{23 * 1024 * 1024, "23M"},
+		{5 * 1024 * 1024 * 1024, "5.0G"},
+	}
+
+	for _, test := range tests {
+		if out := HumanSize(test.input); out != test.output {
+			t.Fatalf("Unexpected output for %v: {Expected: %v, Actual: %v}", test.input, test.output, out)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input  string
+		output int64
+	}{
+		{"0", 0},
+		{"512", 512},
+		{"1K", 1024},
+		{"16K", 16 * 1024},
+		{"64M", 64 * 1024 * 1024},
+		{"5G", 5 * 1024 * 1024 * 1024},
+		{"2T", 2 * 1024 * 1024 * 1024 * 1024},
+	}
+
+	for _, test := range tests {
+		if out, err := ParseSize(test.input); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", test.input, err)
+		} else if out != test.output {
+			t.Fatalf("Unexpected output for %v: {Expected: %v, Actual: %v}", test.input, test.output, out)
+		}
+	}
+
+	for _, input := range []string{"", "M", "abc", "-1", "1.5M", "10X", "8388608T", "9223372036854775808"} {
+		if _, err := ParseSize(input); err == nil {
+			t.Fatalf("Expected error for invalid size: %v", input)
+		} else if err.Error() != "Invalid size: "+input {
+			t.Fatalf("Expected the invalid size to be reported: {Expected: %v, Actual: %v}", input, err)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package command
+
+// outputWriter adapts an Outputter to an io.Writer, so that object contents can be streamed to the output.
+type outputWriter struct {
+	out Outputter
+}
+
+// Write outputs the bytes provided as a string.
+func (w outputWriter) Write(b []byte) (int, error) {
+	w.out.Write(string(b))
+	return len(b), nil
+}
@@ -0,0 +1,22 @@
+package command
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_outputWriter(t *testing.T) {
+	var out mockOutputter
+	w := outputWriter{&out}
+
+	if n, err := io.Copy(w, strings.NewReader("Hello Output")); err != nil {
+		t.Fatal(err)
+	} else if n != 12 {
+		t.Fatalf("Unexpected bytes written: %v", n)
+	}
+
+	if strings.Join(out.output, "") != "Hello Output" {
+		t.Fatalf("Unexpected output: %v", out.output)
+	}
+}
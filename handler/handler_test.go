@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"io"
 	"os"
+
+	"github.com/KyleBanks/s3fs/client"
 )
 
 // Mock indicator
@@ -34,22 +37,44 @@ func (m *mockOutputter) Write(s string) {
 
 type mockS3Client struct {
 	lsBucketsCallback func() ([]string, error)
-	lsObjectsCallback func(string, string) ([]string, error)
+	lsObjectsCallback func(string, string, func([]client.Object) bool) error
+	lsDirCallback     func(string, string, func([]string, []client.Object) bool) error
 
 	bucketExistsCallback func(string) (bool, error)
 	objectExistsCallback func(string, string) (bool, error)
 	pathExistsCallback   func(string, string) (bool, error)
-
-	downloadObjectCallback func(string, string) (string, error)
-	uploadObjectCallback   func(string, string, *os.File) (string, error)
+	headObjectCallback   func(string, string) (client.ObjectInfo, error)
+	bucketInfoCallback   func(string) (client.BucketInfo, error)
+
+	openObjectCallback      func(string, string) (io.ReadCloser, error)
+	openObjectRangeCallback func(string, string, int64, int64) (io.ReadCloser, error)
+	downloadFileCallback    func(string, string, string, client.DownloadOptions) error
+	uploadObjectCallback    func(string, string, *os.File, client.UploadOptions) (string, error)
+	putObjectCallback       func(string, string, *os.File, client.UploadOptions) error
+	deleteObjectCallback    func(string, string) error
+	deleteObjectsCallback   func(string, []string) ([]client.DeleteError, error)
+	copyObjectCallback      func(string, string, string, string) error
+	createFolderCallback    func(string, string) error
+
+	createBucketCallback         func(string, string) error
+	deleteBucketCallback         func(string) error
+	lsObjectVersionsCallback     func(string, string, func([]client.ObjectVersion) bool) error
+	deleteObjectVersionsCallback func(string, []client.ObjectVersion) ([]client.DeleteError, error)
+
+	transfersCallback       func() ([]client.Transfer, error)
+	discardTransferCallback func(client.Transfer) error
 }
 
 func (m mockS3Client) LsBuckets() ([]string, error) {
 	return m.lsBucketsCallback()
 }
 
-func (m mockS3Client) LsObjects(bucket, prefix string) ([]string, error) {
-	return m.lsObjectsCallback(bucket, prefix)
+func (m mockS3Client) LsObjects(bucket, prefix string, fn func([]client.Object) bool) error {
+	return m.lsObjectsCallback(bucket, prefix, fn)
+}
+
+func (m mockS3Client) LsDir(bucket, prefix string, fn func([]string, []client.Object) bool) error {
+	return m.lsDirCallback(bucket, prefix, fn)
 }
 
 func (m mockS3Client) BucketExists(bucket string) (bool, error) {
@@ -64,10 +89,70 @@ func (m mockS3Client) PathExists(bucket, path string) (bool, error) {
 	return m.pathExistsCallback(bucket, path)
 }
 
-func (m mockS3Client) DownloadObject(bucket, key string) (string, error) {
-	return m.downloadObjectCallback(bucket, key)
+func (m mockS3Client) HeadObject(bucket, key string) (client.ObjectInfo, error) {
+	return m.headObjectCallback(bucket, key)
+}
+
+func (m mockS3Client) GetBucketInfo(bucket string) (client.BucketInfo, error) {
+	return m.bucketInfoCallback(bucket)
+}
+
+func (m mockS3Client) OpenObject(bucket, key string) (io.ReadCloser, error) {
+	return m.openObjectCallback(bucket, key)
+}
+
+func (m mockS3Client) OpenObjectRange(bucket, key string, start, end int64) (io.ReadCloser, error) {
+	return m.openObjectRangeCallback(bucket, key, start, end)
+}
+
+func (m mockS3Client) DownloadFile(bucket, key, path string, opts client.DownloadOptions) error {
+	return m.downloadFileCallback(bucket, key, path, opts)
+}
+
+func (m mockS3Client) UploadObject(bucket, key string, file *os.File, opts client.UploadOptions) (string, error) {
+	return m.uploadObjectCallback(bucket, key, file, opts)
+}
+
+func (m mockS3Client) PutObject(bucket, key string, file *os.File, opts client.UploadOptions) error {
+	return m.putObjectCallback(bucket, key, file, opts)
+}
+
+func (m mockS3Client) DeleteObject(bucket, key string) error {
+	return m.deleteObjectCallback(bucket, key)
+}
+
+func (m mockS3Client) DeleteObjects(bucket string, keys []string) ([]client.DeleteError, error) {
+	return m.deleteObjectsCallback(bucket, keys)
+}
+
+func (m mockS3Client) CopyObject(srcBucket, srcKey, dstBucket, dstKey string) error {
+	return m.copyObjectCallback(srcBucket, srcKey, dstBucket, dstKey)
+}
+
+func (m mockS3Client) CreateFolder(bucket, prefix string) error {
+	return m.createFolderCallback(bucket, prefix)
+}
+
+func (m mockS3Client) CreateBucket(bucket, region string) error {
+	return m.createBucketCallback(bucket, region)
+}
+
+func (m mockS3Client) DeleteBucket(bucket string) error {
+	return m.deleteBucketCallback(bucket)
+}
+
+func (m mockS3Client) LsObjectVersions(bucket, prefix string, fn func([]client.ObjectVersion) bool) error {
+	return m.lsObjectVersionsCallback(bucket, prefix, fn)
+}
+
+func (m mockS3Client) DeleteObjectVersions(bucket string, versions []client.ObjectVersion) ([]client.DeleteError, error) {
+	return m.deleteObjectVersionsCallback(bucket, versions)
+}
+
+func (m mockS3Client) Transfers() ([]client.Transfer, error) {
+	return m.transfersCallback()
 }
 
-func (m mockS3Client) UploadObject(bucket, key string, file *os.File) (string, error) {
-	return m.uploadObjectCallback(bucket, key, file)
+func (m mockS3Client) DiscardTransfer(t client.Transfer) error {
+	return m.discardTransferCallback(t)
 }
@@ -11,6 +11,7 @@ import (
 type S3Handler struct {
 	s3 command.S3Client
 	ui indicator
+	in command.Inputter
 
 	con *context.Context
 }
@@ -48,13 +49,43 @@ func (s S3Handler) commandFromArgs(args []string) (ex command.Executor, err erro
 	switch args[0] {
 
 	case command.CmdLs:
-		ex = command.NewLs(s.s3, s.con)
+		ex = command.NewLs(s.s3, s.con, args[1:])
+	case command.CmdTree:
+		ex = command.NewTree(s.s3, s.con, args[1:])
 	case command.CmdCd:
 		ex = command.NewCd(s.s3, s.con, args[1:])
 	case command.CmdGet:
 		ex = command.NewGet(s.s3, s.con, args[1:])
 	case command.CmdPut:
 		ex = command.NewPut(s.s3, s.con, args[1:])
+	case command.CmdRm:
+		ex = command.NewRm(s.s3, s.con, s.in, args[1:])
+	case command.CmdCp:
+		ex = command.NewCp(s.s3, s.con, args[1:])
+	case command.CmdMv:
+		ex = command.NewMv(s.s3, s.con, args[1:])
+	case command.CmdMkdir:
+		ex = command.NewMkdir(s.s3, s.con, args[1:])
+	case command.CmdMb:
+		ex = command.NewMb(s.s3, args[1:])
+	case command.CmdRb:
+		ex = command.NewRb(s.s3, s.con, args[1:])
+	case command.CmdCat:
+		ex = command.NewCat(s.s3, s.con, s.in, args[1:])
+	case command.CmdHead:
+		ex = command.NewHead(s.s3, s.con, args[1:])
+	case command.CmdTail:
+		ex = command.NewTail(s.s3, s.con, args[1:])
+	case command.CmdStat, command.CmdInfo:
+		ex = command.NewStat(s.s3, s.con, args[1:])
+	case command.CmdDu:
+		ex = command.NewDu(s.s3, s.con, args[1:])
+	case command.CmdFind:
+		ex = command.NewFind(s.s3, s.con, s.in, args[1:])
+	case command.CmdSync:
+		ex = command.NewSync(s.s3, s.con, args[1:])
+	case command.CmdResume:
+		ex = command.NewResume(s.s3, s.in, args[1:])
 	case command.CmdPwd:
 		ex = command.NewPwd(s.con)
 	case command.CmdClear:
@@ -70,10 +101,13 @@ func (s S3Handler) commandFromArgs(args []string) (ex command.Executor, err erro
 }
 
 // NewS3 initializes and returns an S3Handler.
-func NewS3(s3 command.S3Client, ui indicator) S3Handler {
+//
+// The Inputter is provided to commands that need to prompt the user for input, such as confirmations.
+func NewS3(s3 command.S3Client, ui indicator, in command.Inputter) S3Handler {
 	return S3Handler{
 		s3:  s3,
 		ui:  ui,
+		in:  in,
 		con: &context.Context{},
 	}
 }
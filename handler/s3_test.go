@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"bufio"
 	"reflect"
 	"strings"
 	"testing"
@@ -12,7 +13,7 @@ func TestS3Handler_Handle(t *testing.T) {
 	// Empty command
 	{
 		var ui mockIndicator
-		s3 := NewS3(nil, &ui)
+		s3 := NewS3(nil, &ui, nil)
 
 		if err := s3.Handle([]string{}, nil); err != nil {
 			t.Fatal(err)
@@ -26,7 +27,7 @@ func TestS3Handler_Handle(t *testing.T) {
 	// Invalid command
 	{
 		var ui mockIndicator
-		s3 := NewS3(nil, &ui)
+		s3 := NewS3(nil, &ui, nil)
 
 		if err := s3.Handle([]string{"fake"}, nil); err == nil {
 			t.Fatal("Expected error for unknown command")
@@ -43,7 +44,7 @@ func TestS3Handler_Handle(t *testing.T) {
 	{
 		var ui mockIndicator
 		var out mockOutputter
-		s3 := NewS3(nil, &ui)
+		s3 := NewS3(nil, &ui, nil)
 
 		if err := s3.Handle([]string{command.CmdPwd}, &out); err != nil {
 			t.Fatal(err)
@@ -68,7 +69,7 @@ func TestS3Handler_Handle(t *testing.T) {
 			return []string{"bucket", "bucket2"}, nil
 		}
 
-		s3 := NewS3(&mockS3, &ui)
+		s3 := NewS3(&mockS3, &ui, nil)
 
 		if err := s3.Handle([]string{command.CmdLs}, &out); err != nil {
 			t.Fatal(err)
@@ -93,15 +94,31 @@ func TestS3Handler_commandFromArgs(t *testing.T) {
 			expected command.Executor
 		}{
 			{command.CmdLs, command.LsCommand{}},
+			{command.CmdTree, command.TreeCommand{}},
 			{command.CmdCd, command.CdCommand{}},
 			{command.CmdGet, command.GetCommand{}},
 			{command.CmdPut, command.PutCommand{}},
+			{command.CmdRm, command.RmCommand{}},
+			{command.CmdCp, command.CpCommand{}},
+			{command.CmdMv, command.MvCommand{}},
+			{command.CmdMkdir, command.MkdirCommand{}},
+			{command.CmdMb, command.MbCommand{}},
+			{command.CmdRb, command.RbCommand{}},
+			{command.CmdCat, command.CatCommand{}},
+			{command.CmdHead, command.HeadCommand{}},
+			{command.CmdTail, command.TailCommand{}},
+			{command.CmdStat, command.StatCommand{}},
+			{command.CmdInfo, command.StatCommand{}},
+			{command.CmdDu, command.DuCommand{}},
+			{command.CmdFind, command.FindCommand{}},
+			{command.CmdSync, command.SyncCommand{}},
+			{command.CmdResume, command.ResumeCommand{}},
 			{command.CmdPwd, command.PwdCommand{}},
 			{command.CmdClear, command.ClearCommand{}},
 			{command.CmdExit, command.ExitCommand{}},
 		}
 
-		s3 := NewS3(nil, nil)
+		s3 := NewS3(nil, nil, nil)
 
 		// For each command, ensure the proper Executor is returned.
 		for _, cmd := range cmds {
@@ -119,7 +136,7 @@ func TestS3Handler_commandFromArgs(t *testing.T) {
 
 	// Unknown Command
 	{
-		s3 := NewS3(nil, nil)
+		s3 := NewS3(nil, nil, nil)
 		unknown := []string{"fake command"}
 
 		if _, err := s3.commandFromArgs(unknown); err == nil {
@@ -133,8 +150,9 @@ func TestS3Handler_commandFromArgs(t *testing.T) {
 func TestNewS3(t *testing.T) {
 	var ui mockIndicator
 	var mockS3 mockS3Client
+	in := bufio.NewScanner(strings.NewReader(""))
 
-	s3 := NewS3(&mockS3, &ui)
+	s3 := NewS3(&mockS3, &ui, in)
 
 	if s3.con == nil {
 		t.Fatalf("Expected S3Handler to be initialized with a Context: %v", s3.con)
@@ -142,5 +160,7 @@ func TestNewS3(t *testing.T) {
 		t.Fatalf("S3Handler storing unknown indicator: %v", s3.ui)
 	} else if s3.s3 != &mockS3 {
 		t.Fatalf("S3Handler storing unknown s3client: %v", s3.s3)
+	} else if s3.in != in {
+		t.Fatalf("S3Handler storing unknown inputter: %v", s3.in)
 	}
 }
@@ -24,8 +24,10 @@ func main() {
 	var h handler.Handler
 	var l listener.Listener
 
-	h = handler.NewS3(client.New("us-east-1"), ui)
-	l = listener.NewText(ui, bufio.NewScanner(os.Stdin))
+	// The handler and listener share the input, so that commands can prompt the user between listens.
+	in := bufio.NewScanner(os.Stdin)
+	h = handler.NewS3(client.New("us-east-1"), ui, in)
+	l = listener.NewText(ui, in)
 
 	// Infinitely listen for and handle user input.
 	for {